@@ -1,16 +1,21 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/wguilherme/gitlife/internal/application/reading"
 	"github.com/wguilherme/gitlife/internal/config"
-	domainReading "github.com/wguilherme/gitlife/internal/domain/reading"
+	"github.com/wguilherme/gitlife/internal/infrastructure/exchange"
 	"github.com/wguilherme/gitlife/internal/infrastructure/git"
 	"github.com/wguilherme/gitlife/internal/infrastructure/storage"
 )
@@ -34,35 +39,34 @@ func main() {
 
 	rootCmd.PersistentFlags().StringVar(&vaultPath, "vault", cfg.VaultPath, "Path to vault directory")
 
-	// Initialize git service if configured
-	initGitService()
-
 	readingCmd := &cobra.Command{
 		Use:   "reading",
 		Short: "Manage reading list",
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			// Update config with CLI flag
-			if vaultPath != cfg.VaultPath {
-				cfg.VaultPath = vaultPath
-			}
+		Long: `Manage reading list.
 
-			var repo domainReading.Repository
-			if gitService != nil {
-				repo = storage.NewMarkdownRepositoryWithGit(cfg, gitService)
-			} else {
-				repo = storage.NewMarkdownRepository(vaultPath)
-			}
-			service = reading.NewService(repo)
-		},
+Items can be referenced by their ID, a unique ID prefix or words from their
+title and author (case and accents are ignored).`,
+		PersistentPreRunE: initReadingService,
 	}
 
 	listCmd := &cobra.Command{
-		Use:   "list",
+		Use:   "list [query]",
 		Short: "List reading items",
-		RunE:  runList,
+		Long: `List reading items, optionally filtered by a query such as:
+
+  gitlife reading list 'type:book rating>=4 tag:go -tag:frontend finished:2025 author:"Martin"'
+
+Fields: title, author, url, doi, venue, notes, review, tag (globs like
+tag:go*), type, status, priority, rating, progress, pages, page, year, added,
+started, finished.
+Operators: ":" (contains / within), "=", "!=", ">", ">=", "<", "<=".
+Terms are combined with AND unless joined by OR; NOT or a leading "-"
+negates a term and parentheses group them. Quote the query so the shell
+does not split it and so "-" terms are not read as flags.`,
+		RunE: runList,
 	}
-	listCmd.Flags().String("status", "", "Filter by status (to-read, reading, done)")
-	listCmd.Flags().String("tag", "", "Filter by tag")
+	addListFlags(listCmd)
+	listCmd.Flags().String("at", "", "List the items as of a date (2025, 2025-03 or 2025-03-14) or git revision")
 
 	addCmd := &cobra.Command{
 		Use:   "add [title]",
@@ -71,10 +75,11 @@ func main() {
 		RunE:  runAdd,
 	}
 	addCmd.Flags().String("author", "", "Author name")
-	addCmd.Flags().String("type", "book", "Item type (book, article, video, course)")
+	addCmd.Flags().String("type", "book", "Item type (book, article, paper, video, course)")
 	addCmd.Flags().String("priority", "medium", "Priority (high, medium, low)")
 	addCmd.Flags().StringSlice("tags", []string{}, "Tags for the item")
 	addCmd.Flags().String("url", "", "URL for the item")
+	addCmd.Flags().Int("pages", 0, "Total length: pages, or minutes for videos and lessons for courses")
 
 	startCmd := &cobra.Command{
 		Use:   "start [id]",
@@ -86,10 +91,38 @@ func main() {
 	progressCmd := &cobra.Command{
 		Use:   "progress [id] [percentage]",
 		Short: "Update reading progress",
-		Args:  cobra.ExactArgs(2),
-		RunE:  runProgress,
+		Long: `Update reading progress by percentage, by position or both. Books and
+articles are tracked in pages, videos in minutes and courses in lessons;
+once the total is set (add/edit --pages) the percentage can be left out
+and is derived from the position.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: runProgress,
+	}
+	progressCmd.Flags().Int("page", 0, "Current page (books and articles)")
+	progressCmd.Flags().Int("minute", 0, "Current minute (videos)")
+	progressCmd.Flags().Int("lesson", 0, "Current lesson (courses)")
+	progressCmd.Flags().Int("minutes", 0, "Length of the reading session in minutes")
+
+	sessionsCmd := &cobra.Command{
+		Use:   "sessions [id]",
+		Short: "Show the reading sessions of an item",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSessions,
+	}
+
+	readsCmd := &cobra.Command{
+		Use:   "reads [id]",
+		Short: "Show every read of an item with its rating and review",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runReads,
+	}
+
+	historyCmd := &cobra.Command{
+		Use:   "history [id]",
+		Short: "Show how an item changed over time, from the vault's git log",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runHistory,
 	}
-	progressCmd.Flags().Int("page", 0, "Current page number")
 
 	finishCmd := &cobra.Command{
 		Use:   "finish [id]",
@@ -100,12 +133,246 @@ func main() {
 	finishCmd.Flags().Int("rating", 0, "Rating (1-5)")
 	finishCmd.Flags().String("review", "", "Review text")
 
-	readingCmd.AddCommand(listCmd, addCmd, startCmd, progressCmd, finishCmd)
+	pauseCmd := &cobra.Command{
+		Use:   "pause [id]",
+		Short: "Put an item you are reading on hold",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runPause,
+	}
+
+	resumeCmd := &cobra.Command{
+		Use:   "resume [id]",
+		Short: "Resume a paused or abandoned item",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runResume,
+	}
+
+	abandonCmd := &cobra.Command{
+		Use:   "abandon [id]",
+		Short: "Give up on an item",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runAbandon,
+	}
+	abandonCmd.Flags().String("reason", "", "Why the item was abandoned")
+
+	rereadCmd := &cobra.Command{
+		Use:   "reread [id]",
+		Short: "Start reading a finished item again",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runReread,
+	}
+
+	editCmd := &cobra.Command{
+		Use:   "edit [id]",
+		Short: "Edit a reading item",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runEdit,
+	}
+	editCmd.Flags().String("title", "", "New title")
+	editCmd.Flags().String("author", "", "Author name")
+	editCmd.Flags().String("type", "", "Item type (book, article, paper, video, course)")
+	editCmd.Flags().String("priority", "", "Priority (high, medium, low)")
+	editCmd.Flags().StringSlice("tags", []string{}, "Tags for the item (replaces existing tags)")
+	editCmd.Flags().String("url", "", "URL for the item")
+	editCmd.Flags().String("notes", "", "Notes for the item")
+	editCmd.Flags().Int("pages", 0, "Total length: pages, or minutes for videos and lessons for courses")
+
+	highlightCmd := &cobra.Command{
+		Use:   "highlight [id] [text]",
+		Short: "Save a quote or passage from an item",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runHighlight,
+	}
+	highlightCmd.Flags().Int("page", 0, "Page of the passage (minute for videos, lesson for courses)")
+	highlightCmd.Flags().String("note", "", "Note about the passage")
+
+	highlightsCmd := &cobra.Command{
+		Use:   "highlights [id]",
+		Short: "Show the highlights of an item",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runHighlights,
+	}
+	highlightsCmd.Flags().Bool("markdown", false, "Print the highlights as a Markdown document")
+
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import items and highlights from other applications",
+	}
+	importCmd.AddCommand(&cobra.Command{
+		Use:   "kindle [My Clippings.txt]",
+		Short: "Import highlights and notes from a Kindle clippings file",
+		Long: `Import highlights and notes from a Kindle "My Clippings.txt" file.
+
+Books are matched by title against the reading list and added when missing.
+Notes are attached to the highlight they were written on and highlights
+already saved are skipped, so the same file can be imported again.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runImportKindle,
+	})
+	importCmd.AddCommand(&cobra.Command{
+		Use:   "goodreads [goodreads_library_export.csv]",
+		Short: "Import a Goodreads library export",
+		Long: `Import a Goodreads library export (My Books > Import and export).
+
+The exclusive shelf sets the status (to-read, currently-reading, read, and
+paused/on-hold or abandoned/did-not-finish custom shelves), the other
+shelves become tags. Books are matched by ISBN or title; existing items
+only get the fields they are missing.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runImportGoodreads,
+	})
+	importCmd.PersistentFlags().Bool("dry-run", false, "Show what would be imported without writing")
+
+	importCmd.AddCommand(&cobra.Command{
+		Use:   "json [file]",
+		Short: "Import items exported with export --format=json or ndjson",
+		Long: `Import items exported with export --format=json or --format=ndjson
+("-" reads from stdin). Items keep their IDs; items already in the vault
+only get the fields they are missing.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runImportJSON,
+	})
+	importCmd.AddCommand(&cobra.Command{
+		Use:   "ndjson [file]",
+		Short: "Import items exported with export --format=ndjson",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runImportJSON,
+	})
+	importCmd.AddCommand(&cobra.Command{
+		Use:   "bibtex [file.bib]",
+		Short: "Import papers from a BibTeX file",
+		Long: `Import papers from a BibTeX file. @book entries become books and every
+other entry a paper, keeping the authors, venue (journal or booktitle),
+year, DOI and URL. Items are matched by DOI, then ISBN or title.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runImportBibTeX,
+	})
+	importCmd.AddCommand(&cobra.Command{
+		Use:   "csl [file.json]",
+		Short: "Import papers from a CSL-JSON file (Zotero, Mendeley)",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runImportCSL,
+	})
+
+	importCmd.AddCommand(&cobra.Command{
+		Use:     "bookmarks [bookmarks.html]",
+		Aliases: []string{"pocket", "instapaper"},
+		Short:   "Import links from browser bookmarks, Pocket or Instapaper",
+		Long: `Import links from a bookmarks HTML file, as exported by browsers, Pocket
+(ril_export.html) and Instapaper. Links become articles; folders and the
+TAGS attribute become tags (nested folders as "programming/go") and
+ADD_DATE the added date. Links in an archive section are marked as done.
+Links already in the vault are matched by URL and skipped.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runImportBookmarks,
+	})
+
+	dedupeCmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "Find and merge duplicate items",
+		Long: `Find items that are probably the same: same ISBN, DOI or URL, or the same
+type and title by a similar author ("Robert Martin" and "Robert C. Martin").
+Duplicates are shown side by side; with --merge each group is merged into
+its oldest item in a single commit. Tags are combined, the earliest added
+date is kept, the status and progress come from the copy that got further
+and notes are joined.`,
+		Args: cobra.NoArgs,
+		RunE: runDedupe,
+	}
+	dedupeCmd.Flags().Bool("merge", false, "Merge every group of duplicates")
+
+	mergeCmd := &cobra.Command{
+		Use:   "merge [id] [duplicate-id]...",
+		Short: "Merge duplicates into the first item",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  runMerge,
+	}
+
+	nextCmd := &cobra.Command{
+		Use:   "next [query]",
+		Short: "Rank to-read items and suggest what to read next",
+		Long: `Rank the to-read items, optionally narrowed by a query as in list, and
+explain each score. The score adds up these factors, each multiplied by
+its weight:
+
+  priority  high and medium priority
+  age       how long the item has been waiting, up to a year
+  affinity  how finished items sharing a tag were rated (below 3 counts against)
+  length    shorter items first
+  wip       with items already in progress, long items score lower
+
+Weights default to 1 and can be changed with --weights or the
+GITLIFE_NEXT_WEIGHTS environment variable, e.g. "affinity=2,age=0.5".`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runNext,
+	}
+	nextCmd.Flags().Int("limit", 5, "Number of items to show")
+	nextCmd.Flags().String("weights", "", "Weights of the factors, e.g. priority=2,age=0.5")
+	nextCmd.Flags().Bool("random", false, "Pick one item at random, weighted by score")
+
+	citeCmd := &cobra.Command{
+		Use:   "cite [id]",
+		Short: "Print a citation of an item",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runCite,
+	}
+	citeCmd.Flags().String("style", "apa", "Citation style (bibtex, apa, mla)")
+
+	exportCmd := &cobra.Command{
+		Use:   "export [query]",
+		Short: "Export the reading list",
+		Long: `Export the reading list as JSON, NDJSON, CSV, YAML, a Goodreads CSV or BibTeX.
+The query and the filters are the same as in list.`,
+		RunE: runExport,
+	}
+	addListFlags(exportCmd)
+	exportCmd.Flags().String("format", "json", "Export format (json, ndjson, csv, yaml, goodreads, bibtex)")
+	exportCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show reading statistics",
+		Args:  cobra.NoArgs,
+		RunE:  runStats,
+	}
+	statsCmd.Flags().String("from", "", "Only items finished from this date (2025, 2025-03 or 2025-03-14)")
+	statsCmd.Flags().String("to", "", "Only items finished up to this date (2025, 2025-03 or 2025-03-14)")
+	statsCmd.Flags().String("group-by", "month", "Group finished items by month or year")
+	statsCmd.Flags().String("at", "", "Compute the stats as of a date (2025, 2025-03 or 2025-03-14) or git revision")
+
+	readingCmd.AddCommand(listCmd, addCmd, editCmd, startCmd, progressCmd, finishCmd,
+		pauseCmd, resumeCmd, abandonCmd, rereadCmd, readsCmd, historyCmd, sessionsCmd,
+		highlightCmd, highlightsCmd, nextCmd, citeCmd, importCmd, exportCmd, dedupeCmd, mergeCmd, statsCmd)
 
 	// Add vault commands
 	vaultCmd := createVaultCommand()
 
-	rootCmd.AddCommand(readingCmd, vaultCmd)
+	mergeDriverCmd := &cobra.Command{
+		Use:   "merge-driver [base] [current] [other] [path]",
+		Short: "Merge versions of a reading file item by item (git merge driver)",
+		Long: `Merge the current and other versions of the reading list, or of a reading
+note, item by item, the way gitlife vault sync does. Git runs it as the
+gitlife merge driver that gitlife vault init and clone set up:
+
+  [merge "gitlife"]
+      driver = gitlife merge-driver %O %A %B %P
+
+The result is written to the current file. When both sides changed the same
+property of the same item, the file is merged line by line instead, with the
+conflicting lines marked, and the command fails so git asks for a manual
+resolution.`,
+		Args:         cobra.RangeArgs(3, 4),
+		SilenceUsage: true,
+		RunE:         runMergeDriver,
+	}
+
+	rootCmd.AddCommand(readingCmd, createTagsCommand(), vaultCmd, mergeDriverCmd)
+
+	// Initialize git service if configured; the merge driver runs inside a
+	// git merge and leaves the vault alone
+	if command, _, err := rootCmd.Find(os.Args[1:]); err != nil || command != mergeDriverCmd {
+		initGitService()
+	}
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -113,6 +380,38 @@ func main() {
 	}
 }
 
+// initReadingService opens the vault for the commands working on the
+// reading list.
+func initReadingService(cmd *cobra.Command, args []string) error {
+	// Update config with CLI flag
+	if vaultPath != cfg.VaultPath {
+		cfg.VaultPath = vaultPath
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	service = reading.NewService(storage.NewRepository(cfg, gitService))
+	return nil
+}
+
+// serviceAt returns the reading service for the --at flag: the reading list
+// as a past commit of the vault left it, read-only, or the current one.
+func serviceAt(cmd *cobra.Command) (*reading.Service, error) {
+	at, _ := cmd.Flags().GetString("at")
+	if at == "" {
+		return service, nil
+	}
+
+	repo, err := storage.NewRevisionRepository(cfg, gitService, at)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "As of %s (%s)\n", repo.Time().Format("2006-01-02 15:04"), repo.Commit().Hash[:7])
+	return reading.NewService(repo), nil
+}
+
 func initGitService() {
 	// Only initialize if vault repo is configured
 	if cfg.VaultRepo == "" {
@@ -134,6 +433,8 @@ func initGitService() {
 			if err := gitService.Clone(); err != nil {
 				log.Printf("Failed to clone repository: %v", err)
 				log.Println("You can manually clone with: gitlife vault clone")
+			} else if err := gitService.InstallMergeDriver(); err != nil {
+				log.Printf("Warning: failed to set up the merge driver: %v", err)
 			}
 		}
 	}
@@ -171,10 +472,119 @@ func createVaultCommand() *cobra.Command {
 		RunE:  runVaultSync,
 	}
 
-	vaultCmd.AddCommand(initCmd, cloneCmd, statusCmd, syncCmd)
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Convert the reading list between single-file and one-note-per-item storage",
+		RunE:  runVaultMigrate,
+	}
+	migrateCmd.Flags().String("to", "", "Target storage layout (single, notes)")
+	migrateCmd.MarkFlagRequired("to")
+
+	vaultCmd.AddCommand(initCmd, cloneCmd, statusCmd, syncCmd, migrateCmd)
 	return vaultCmd
 }
 
+func createTagsCommand() *cobra.Command {
+	tagsCmd := &cobra.Command{
+		Use:   "tags",
+		Short: "Manage the tags of the reading list",
+		Long: `Manage the tags of the reading list.
+
+Tags can be nested with "/", as in programming/go; filtering by a tag also
+finds the items tagged with the tags nested under it. Renaming, merging and
+deleting a tag also apply to its nested tags and are saved in one commit.`,
+		PersistentPreRunE: initReadingService,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List tags with the number of items using them",
+		Args:  cobra.NoArgs,
+		RunE:  runTagsList,
+	}
+
+	renameCmd := &cobra.Command{
+		Use:   "rename [tag] [new-tag]",
+		Short: "Rename a tag and the tags nested under it",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runTagsRename,
+	}
+
+	mergeCmd := &cobra.Command{
+		Use:   "merge [tag]... [into-tag]",
+		Short: "Replace tags with another tag",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  runTagsMerge,
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete [tag]",
+		Short: "Remove a tag and the tags nested under it from every item",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runTagsDelete,
+	}
+
+	tagsCmd.AddCommand(listCmd, renameCmd, mergeCmd, deleteCmd)
+	return tagsCmd
+}
+
+func runTagsList(cmd *cobra.Command, args []string) error {
+	tags, err := service.Tags()
+	if err != nil {
+		return err
+	}
+
+	if len(tags) == 0 {
+		fmt.Println("No tags found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TAG\tITEMS\tWITH NESTED")
+	fmt.Fprintln(w, "---\t-----\t-----------")
+	for _, tag := range tags {
+		depth := strings.Count(tag.Name, "/")
+		name := strings.Repeat("  ", depth) + tag.Name[strings.LastIndex(tag.Name, "/")+1:]
+		nested := ""
+		if tag.Total != tag.Count {
+			nested = fmt.Sprintf("%d", tag.Total)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", name, tag.Count, nested)
+	}
+	return w.Flush()
+}
+
+func runTagsRename(cmd *cobra.Command, args []string) error {
+	changed, err := service.RenameTag(reading.RenameTagCommand{From: args[0], To: args[1]})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Renamed %s to %s on %d items\n", args[0], args[1], changed)
+	return nil
+}
+
+func runTagsMerge(cmd *cobra.Command, args []string) error {
+	from, to := args[:len(args)-1], args[len(args)-1]
+	changed, err := service.MergeTags(reading.MergeTagsCommand{From: from, To: to})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Merged %s into %s on %d items\n", strings.Join(from, ", "), to, changed)
+	return nil
+}
+
+func runTagsDelete(cmd *cobra.Command, args []string) error {
+	changed, err := service.DeleteTag(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed %s from %d items\n", args[0], changed)
+	return nil
+}
+
 func runVaultInit(cmd *cobra.Command, args []string) error {
 	remote, _ := cmd.Flags().GetString("remote")
 
@@ -190,6 +600,9 @@ func runVaultInit(cmd *cobra.Command, args []string) error {
 	if err := svc.Init(); err != nil {
 		return err
 	}
+	if err := svc.InstallMergeDriver(); err != nil {
+		return err
+	}
 
 	fmt.Printf("Initialized vault repository at %s\n", cfg.VaultPath)
 	if remote != "" {
@@ -209,11 +622,45 @@ func runVaultClone(cmd *cobra.Command, args []string) error {
 	if err := svc.Clone(); err != nil {
 		return err
 	}
+	if err := svc.InstallMergeDriver(); err != nil {
+		return err
+	}
 
 	fmt.Printf("Cloned vault repository to %s\n", cfg.VaultPath)
 	return nil
 }
 
+func runMergeDriver(cmd *cobra.Command, args []string) error {
+	basePath, currentPath, otherPath := args[0], args[1], args[2]
+	name := currentPath
+	if len(args) == 4 {
+		name = args[3]
+	}
+
+	versions := make([][]byte, 3)
+	for k, path := range []string{basePath, currentPath, otherPath} {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		versions[k] = content
+	}
+
+	merged, conflicts, err := storage.NewMerger(cfg).Merge(name, versions[0], versions[1], versions[2])
+	if err == nil && len(conflicts) == 0 {
+		return os.WriteFile(currentPath, merged, 0644)
+	}
+
+	// Leave the file merged line by line for a manual resolution
+	if _, textErr := git.MergeText(currentPath, basePath, otherPath); textErr != nil {
+		return textErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to merge %s item by item: %w", name, err)
+	}
+	return fmt.Errorf("%s needs manual resolution:\n  %s", name, strings.Join(conflicts, "\n  "))
+}
+
 func runVaultStatus(cmd *cobra.Command, args []string) error {
 	if gitService == nil {
 		var err error
@@ -229,6 +676,19 @@ func runVaultStatus(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if gitService.MergeInProgress() {
+		conflicts, err := gitService.Conflicts()
+		if err != nil {
+			return err
+		}
+		fmt.Println("Merge waiting for manual resolution, conflicts in:")
+		for _, path := range conflicts {
+			fmt.Printf("  %s\n", path)
+		}
+		fmt.Println("Resolve them and run: gitlife vault sync")
+		return nil
+	}
+
 	status, err := gitService.Status()
 	if err != nil {
 		return err
@@ -259,19 +719,15 @@ func runVaultSync(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no vault repository found")
 	}
 
-	// Pull
-	fmt.Println("Pulling latest changes...")
-	if err := gitService.Pull(); err != nil {
-		log.Printf("Warning: pull failed: %v", err)
-	}
+	gitService.SetMerger(storage.NewMerger(cfg))
 
-	// Check for local changes
+	// Commit local changes first, so the pull can merge them
 	hasChanges, err := gitService.HasChanges()
 	if err != nil {
 		return err
 	}
 
-	if hasChanges {
+	if hasChanges && !gitService.MergeInProgress() {
 		fmt.Println("Committing local changes...")
 		if err := gitService.Add([]string{"."}); err != nil {
 			return err
@@ -279,9 +735,27 @@ func runVaultSync(cmd *cobra.Command, args []string) error {
 		if err := gitService.Commit("Manual sync from gitlife"); err != nil {
 			return err
 		}
+	}
+
+	// Pull, merging the reading list item by item if needed
+	fmt.Println("Pulling latest changes...")
+	pulled := true
+	if err := gitService.Pull(); err != nil {
+		var conflict *git.ConflictError
+		if errors.As(err, &conflict) {
+			return err
+		}
+		log.Printf("Warning: pull failed: %v", err)
+		pulled = false
+	}
+
+	if hasChanges || pulled {
 		if err := gitService.Push(); err != nil {
 			return err
 		}
+	}
+
+	if hasChanges {
 		fmt.Println("Changes pushed successfully")
 	} else {
 		fmt.Println("No local changes to sync")
@@ -290,25 +764,69 @@ func runVaultSync(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func runList(cmd *cobra.Command, args []string) error {
-	status, _ := cmd.Flags().GetString("status")
-	tag, _ := cmd.Flags().GetString("tag")
+func runVaultMigrate(cmd *cobra.Command, args []string) error {
+	target, _ := cmd.Flags().GetString("to")
 
-	var items []reading.ItemDTO
-	var err error
+	if vaultPath != cfg.VaultPath {
+		cfg.VaultPath = vaultPath
+	}
 
-	if status != "" {
-		items, err = service.ListByStatus(status)
-	} else if tag != "" {
-		items, err = service.ListByTag(tag)
-	} else {
-		items, err = service.ListAll()
+	count, err := storage.Migrate(cfg, gitService, target)
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		fmt.Println("No items to migrate")
+		return nil
 	}
 
+	fmt.Printf("Migrated %d items to %s storage\n", count, target)
+	if cfg.Storage != target {
+		fmt.Printf("Set GITLIFE_STORAGE=%s to use the new layout\n", target)
+	}
+	return nil
+}
+
+// addListFlags adds the filters shared by list and export.
+func addListFlags(cmd *cobra.Command) {
+	cmd.Flags().String("status", "", "Filter by status (to-read, reading, paused, done, abandoned)")
+	cmd.Flags().StringSlice("tag", []string{}, "Filter by tag (repeatable, items must have all tags; nested tags match their parent)")
+	cmd.Flags().String("type", "", "Filter by type (book, article, paper, video, course)")
+	cmd.Flags().String("priority", "", "Filter by priority (high, medium, low)")
+	cmd.Flags().String("search", "", "Search title, author, tags, URL, notes and review")
+	cmd.Flags().String("sort", "", "Sort by added, started, finished, priority, rating or title (prefix with - for descending)")
+	cmd.Flags().Int("limit", 0, "Maximum number of items to show")
+	cmd.Flags().Int("offset", 0, "Number of items to skip")
+}
+
+func listQuery(cmd *cobra.Command, args []string) reading.ListQuery {
+	flags := cmd.Flags()
+	query := reading.ListQuery{Expression: strings.Join(args, " ")}
+	query.Status, _ = flags.GetString("status")
+	query.Tags, _ = flags.GetStringSlice("tag")
+	query.Type, _ = flags.GetString("type")
+	query.Priority, _ = flags.GetString("priority")
+	query.Search, _ = flags.GetString("search")
+	query.Sort, _ = flags.GetString("sort")
+	query.Limit, _ = flags.GetInt("limit")
+	query.Offset, _ = flags.GetInt("offset")
+	return query
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	query := listQuery(cmd, args)
+
+	svc, err := serviceAt(cmd)
+	if err != nil {
+		return err
+	}
+	result, err := svc.Query(query)
 	if err != nil {
 		return err
 	}
 
+	items := result.Items
 	if len(items) == 0 {
 		fmt.Println("No items found")
 		return nil
@@ -333,7 +851,14 @@ func runList(cmd *cobra.Command, args []string) error {
 		)
 	}
 
-	return w.Flush()
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if result.Count < result.Total {
+		fmt.Printf("\nShowing %d-%d of %d items\n", query.Offset+1, query.Offset+result.Count, result.Total)
+	}
+	return nil
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
@@ -343,21 +868,71 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	priority, _ := cmd.Flags().GetString("priority")
 	tags, _ := cmd.Flags().GetStringSlice("tags")
 	url, _ := cmd.Flags().GetString("url")
+	pages, _ := cmd.Flags().GetInt("pages")
 
 	command := reading.AddItemCommand{
-		Title:    title,
-		Author:   author,
-		Type:     itemType,
-		Priority: priority,
-		Tags:     tags,
-		URL:      url,
+		Title:      title,
+		Author:     author,
+		Type:       itemType,
+		Priority:   priority,
+		Tags:       tags,
+		URL:        url,
+		TotalPages: pages,
+	}
+
+	item, err := service.AddItem(command)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Added: %s (%s)\n", item.Title, item.ID)
+	return nil
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	command := reading.UpdateItemCommand{ItemID: id}
+	flags := cmd.Flags()
+
+	if flags.Changed("title") {
+		title, _ := flags.GetString("title")
+		command.Title = &title
+	}
+	if flags.Changed("author") {
+		author, _ := flags.GetString("author")
+		command.Author = &author
+	}
+	if flags.Changed("type") {
+		itemType, _ := flags.GetString("type")
+		command.Type = &itemType
+	}
+	if flags.Changed("priority") {
+		priority, _ := flags.GetString("priority")
+		command.Priority = &priority
+	}
+	if flags.Changed("tags") {
+		tags, _ := flags.GetStringSlice("tags")
+		command.Tags = &tags
+	}
+	if flags.Changed("url") {
+		url, _ := flags.GetString("url")
+		command.URL = &url
+	}
+	if flags.Changed("notes") {
+		notes, _ := flags.GetString("notes")
+		command.Notes = &notes
+	}
+	if flags.Changed("pages") {
+		pages, _ := flags.GetInt("pages")
+		command.TotalPages = &pages
 	}
 
-	if err := service.AddItem(command); err != nil {
+	item, err := service.UpdateItem(command)
+	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Added: %s\n", title)
+	fmt.Printf("Updated: %s (%s)\n", item.Title, item.ID)
 	return nil
 }
 
@@ -372,40 +947,672 @@ func runStart(cmd *cobra.Command, args []string) error {
 
 func runProgress(cmd *cobra.Command, args []string) error {
 	id := args[0]
-	percentage := 0
-	fmt.Sscanf(args[1], "%d", &percentage)
-	page, _ := cmd.Flags().GetInt("page")
+	flags := cmd.Flags()
+	minutes, _ := flags.GetInt("minutes")
 
 	command := reading.UpdateProgressCommand{
-		ItemID:      id,
-		Percentage:  percentage,
-		CurrentPage: page,
+		ItemID:  id,
+		Minutes: minutes,
+	}
+
+	if len(args) > 1 {
+		percentage, err := strconv.Atoi(strings.TrimSuffix(args[1], "%"))
+		if err != nil {
+			return fmt.Errorf("invalid percentage: %s", args[1])
+		}
+		command.Percentage = &percentage
+	}
+
+	units := map[string]string{"page": "pages", "minute": "minutes", "lesson": "lessons"}
+	for flag, unit := range units {
+		if !flags.Changed(flag) {
+			continue
+		}
+		if command.Unit != "" {
+			return fmt.Errorf("use only one of --page, --minute and --lesson")
+		}
+		command.CurrentPage, _ = flags.GetInt(flag)
+		command.Unit = unit
 	}
 
-	if err := service.UpdateProgress(command); err != nil {
+	item, err := service.UpdateProgress(command)
+	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Updated progress for: %s (%d%%)\n", id, percentage)
+	position := ""
+	if item.CurrentPage > 0 {
+		position = fmt.Sprintf(", %s %d", strings.TrimSuffix(item.Unit, "s"), item.CurrentPage)
+		if item.TotalPages > 0 {
+			position += fmt.Sprintf(" of %d", item.TotalPages)
+		}
+	}
+	fmt.Printf("Updated progress for: %s (%d%%%s)\n", item.Title, item.Progress, position)
 	return nil
 }
 
-func runFinish(cmd *cobra.Command, args []string) error {
-	id := args[0]
-	rating, _ := cmd.Flags().GetInt("rating")
-	review, _ := cmd.Flags().GetString("review")
+func runSessions(cmd *cobra.Command, args []string) error {
+	sessions, err := service.Sessions(args[0])
+	if err != nil {
+		return err
+	}
 
-	command := reading.FinishItemCommand{
-		ItemID: id,
-		Rating: rating,
-		Review: review,
+	if len(sessions) == 0 {
+		fmt.Println("No sessions logged")
+		return nil
 	}
 
-	if err := service.FinishReading(command); err != nil {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	unit := strings.ToUpper(sessions[0].Unit)
+	fmt.Fprintf(w, "DATE\t%s\tPROGRESS\tMINUTES\n", unit)
+	fmt.Fprintf(w, "----\t%s\t--------\t-------\n", strings.Repeat("-", len(unit)))
+
+	totalPages, totalMinutes := 0, 0
+	for _, session := range sessions {
+		pages := ""
+		if session.ToPage > 0 {
+			pages = fmt.Sprintf("%d-%d (%d)", session.FromPage, session.ToPage, session.Pages)
+		}
+		minutes := ""
+		if session.Minutes > 0 {
+			minutes = fmt.Sprintf("%d", session.Minutes)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d%%\t%s\n", session.Date.Format("2006-01-02"), pages, session.Percentage, minutes)
+
+		totalPages += session.Pages
+		totalMinutes += session.Minutes
+	}
+
+	if err := w.Flush(); err != nil {
 		return err
 	}
 
-	fmt.Printf("Finished: %s\n", id)
+	fmt.Printf("\n%d sessions, %d %s, %d minutes\n", len(sessions), totalPages, sessions[0].Unit, totalMinutes)
+	return nil
+}
+
+func runReads(cmd *cobra.Command, args []string) error {
+	reads, err := service.ReadThroughs(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(reads) == 0 {
+		fmt.Println("Not finished yet")
+		return nil
+	}
+
+	date := func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return t.Format("2006-01-02")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STARTED\tFINISHED\tRATING\tREVIEW")
+	fmt.Fprintln(w, "-------\t--------\t------\t------")
+	for _, read := range reads {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", date(read.Started), date(read.Finished),
+			strings.Repeat("⭐", read.Rating), read.Review)
+	}
+	return w.Flush()
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	history, err := service.History(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(history) == 0 {
+		fmt.Println("No history yet, the item has not been committed")
+		return nil
+	}
+
+	value := func(text string) string {
+		if text == "" {
+			return "(none)"
+		}
+		return text
+	}
+
+	for i, entry := range history {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s  %s  %s  %s\n", entry.Date.Local().Format("2006-01-02 15:04"), entry.Commit, entry.Author, entry.Message)
+		for _, change := range entry.Changes {
+			switch {
+			case change.Field == "item":
+				fmt.Printf("    %s\n", change.To)
+			case change.From == "":
+				fmt.Printf("    %s: %s\n", change.Field, change.To)
+			default:
+				fmt.Printf("    %s: %s → %s\n", change.Field, change.From, value(change.To))
+			}
+		}
+	}
+	return nil
+}
+
+func runHighlight(cmd *cobra.Command, args []string) error {
+	page, _ := cmd.Flags().GetInt("page")
+	note, _ := cmd.Flags().GetString("note")
+
+	highlight, err := service.AddHighlight(reading.AddHighlightCommand{
+		ItemID: args[0],
+		Text:   args[1],
+		Page:   page,
+		Note:   note,
+	})
+	if err != nil {
+		return err
+	}
+
+	position := ""
+	if highlight.Page > 0 {
+		position = fmt.Sprintf(" (%s %d)", strings.TrimSuffix(highlight.Unit, "s"), highlight.Page)
+	}
+	fmt.Printf("Saved highlight%s: %q\n", position, highlight.Text)
+	return nil
+}
+
+func runHighlights(cmd *cobra.Command, args []string) error {
+	if markdown, _ := cmd.Flags().GetBool("markdown"); markdown {
+		document, err := service.HighlightsMarkdown(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Print(document)
+		return nil
+	}
+
+	highlights, err := service.Highlights(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(highlights) == 0 {
+		fmt.Println("No highlights saved")
+		return nil
+	}
+
+	for _, highlight := range highlights {
+		position := ""
+		if highlight.Page > 0 {
+			position = fmt.Sprintf(", %s %d", strings.TrimSuffix(highlight.Unit, "s"), highlight.Page)
+		}
+		fmt.Printf("%s%s\n  %q\n", highlight.Added.Format("2006-01-02"), position, highlight.Text)
+		if highlight.Note != "" {
+			fmt.Printf("  %s\n", highlight.Note)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func runFinish(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	rating, _ := cmd.Flags().GetInt("rating")
+	review, _ := cmd.Flags().GetString("review")
+
+	command := reading.FinishItemCommand{
+		ItemID: id,
+		Rating: rating,
+		Review: review,
+	}
+
+	if err := service.FinishReading(command); err != nil {
+		return err
+	}
+
+	fmt.Printf("Finished: %s\n", id)
+	return nil
+}
+
+func runImportKindle(cmd *cobra.Command, args []string) error {
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open clippings: %w", err)
+	}
+	defer file.Close()
+
+	records, skipped, err := exchange.ParseKindleClippings(file)
+	if err != nil {
+		return err
+	}
+
+	if err := runImport(cmd, records); err != nil {
+		return err
+	}
+	if skipped > 0 {
+		fmt.Printf("%d bookmarks and notes without a highlight skipped\n", skipped)
+	}
+	return nil
+}
+
+func runImportGoodreads(cmd *cobra.Command, args []string) error {
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open Goodreads export: %w", err)
+	}
+	defer file.Close()
+
+	records, err := exchange.ParseGoodreadsCSV(file)
+	if err != nil {
+		return err
+	}
+	return runImport(cmd, records)
+}
+
+func runImportJSON(cmd *cobra.Command, args []string) error {
+	var input io.Reader = os.Stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open export: %w", err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	records, err := exchange.ParseJSON(input)
+	if err != nil {
+		return err
+	}
+	return runImport(cmd, records)
+}
+
+func runImportBibTeX(cmd *cobra.Command, args []string) error {
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open BibTeX file: %w", err)
+	}
+	defer file.Close()
+
+	records, err := exchange.ParseBibTeX(file)
+	if err != nil {
+		return err
+	}
+	return runImport(cmd, records)
+}
+
+func runImportCSL(cmd *cobra.Command, args []string) error {
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open CSL-JSON file: %w", err)
+	}
+	defer file.Close()
+
+	records, err := exchange.ParseCSLJSON(file)
+	if err != nil {
+		return err
+	}
+	return runImport(cmd, records)
+}
+
+func runImportBookmarks(cmd *cobra.Command, args []string) error {
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open bookmarks: %w", err)
+	}
+	defer file.Close()
+
+	records, err := exchange.ParseBookmarksHTML(file)
+	if err != nil {
+		return err
+	}
+	return runImport(cmd, records)
+}
+
+func runCite(cmd *cobra.Command, args []string) error {
+	style, _ := cmd.Flags().GetString("style")
+
+	item, err := service.GetItem(args[0])
+	if err != nil {
+		return err
+	}
+
+	citation, err := exchange.Cite(*item, style)
+	if err != nil {
+		return err
+	}
+	fmt.Println(strings.TrimSuffix(citation, "\n"))
+	return nil
+}
+
+// runImport imports records and prints what changed, honoring --dry-run.
+func runImport(cmd *cobra.Command, records []reading.ImportRecord) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	report, err := service.Import(reading.ImportCommand{Records: records, DryRun: dryRun})
+	if err != nil {
+		return err
+	}
+
+	if len(report.Items) == 0 {
+		fmt.Println("Nothing to import")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tCHANGE\tHIGHLIGHTS")
+	fmt.Fprintln(w, "--\t-----\t------\t------\t----------")
+	for _, item := range report.Items {
+		change := "unchanged"
+		switch {
+		case item.Created:
+			change = "added"
+		case len(item.Fields) > 0:
+			change = "filled " + strings.Join(item.Fields, ", ")
+		}
+		highlights := ""
+		if item.Highlights > 0 || item.Duplicates > 0 {
+			highlights = fmt.Sprintf("%d new, %d already saved", item.Highlights, item.Duplicates)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", item.ID, item.Title, item.Status, change, highlights)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%d items added, %d updated, %d unchanged, %d highlights added\n",
+		report.Created, report.Updated, report.Unchanged, report.Highlights)
+	if report.DryRun {
+		fmt.Println("Dry run: nothing was written")
+	}
+	return nil
+}
+
+func runNext(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	spec, _ := cmd.Flags().GetString("weights")
+	random, _ := cmd.Flags().GetBool("random")
+
+	weights, err := reading.ParseNextWeights(cfg.NextWeights, reading.DefaultNextWeights)
+	if err != nil {
+		return fmt.Errorf("invalid GITLIFE_NEXT_WEIGHTS: %w", err)
+	}
+	if weights, err = reading.ParseNextWeights(spec, weights); err != nil {
+		return err
+	}
+
+	query := reading.NextQuery{Limit: limit, Weights: weights, Random: random}
+	if len(args) > 0 {
+		query.Expression = args[0]
+	}
+
+	result, err := service.Next(query)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Items) == 0 {
+		fmt.Println("Nothing to read next")
+		return nil
+	}
+
+	printRecommendation := func(title string, rec reading.RecommendationDTO) {
+		fmt.Printf("%s %s (%s) by %s, score %.2f\n", title, rec.Item.Title, rec.Item.ID, rec.Item.Author, rec.Score)
+		for _, reason := range rec.Reasons {
+			fmt.Printf("   %+.2f  %s\n", reason.Points, reason.Detail)
+		}
+	}
+
+	if result.Picked != nil {
+		printRecommendation("Picked:", *result.Picked)
+		return nil
+	}
+
+	for i, rec := range result.Items {
+		if i > 0 {
+			fmt.Println()
+		}
+		printRecommendation(fmt.Sprintf("%d.", i+1), rec)
+	}
+	if len(result.Items) < result.Total {
+		fmt.Printf("\nShowing %d of %d to-read items\n", len(result.Items), result.Total)
+	}
+	return nil
+}
+
+func runDedupe(cmd *cobra.Command, args []string) error {
+	if merge, _ := cmd.Flags().GetBool("merge"); merge {
+		merged, err := service.MergeDuplicates()
+		if err != nil {
+			return err
+		}
+		if len(merged) == 0 {
+			fmt.Println("No duplicates found")
+			return nil
+		}
+		for _, item := range merged {
+			fmt.Printf("Merged into %s: %s\n", item.ID, item.Title)
+		}
+		return nil
+	}
+
+	groups, err := service.Duplicates()
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		fmt.Println("No duplicates found")
+		return nil
+	}
+
+	for _, group := range groups {
+		fmt.Printf("Same %s:\n", strings.Join(group.Reasons, ", "))
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		row := func(label string, value func(reading.ItemDTO) string) {
+			fmt.Fprintf(w, "  %s", label)
+			for _, item := range group.Items {
+				fmt.Fprintf(w, "\t%s", truncate(value(item), 30))
+			}
+			fmt.Fprintln(w)
+		}
+		row("ID", func(item reading.ItemDTO) string { return item.ID })
+		row("TITLE", func(item reading.ItemDTO) string { return item.Title })
+		row("AUTHOR", func(item reading.ItemDTO) string { return item.Author })
+		row("TYPE", func(item reading.ItemDTO) string { return item.Type })
+		row("STATUS", func(item reading.ItemDTO) string {
+			if item.Progress > 0 && item.Status != "done" {
+				return fmt.Sprintf("%s (%d%%)", item.Status, item.Progress)
+			}
+			return item.Status
+		})
+		row("TAGS", func(item reading.ItemDTO) string { return strings.Join(item.Tags, " ") })
+		row("ADDED", func(item reading.ItemDTO) string { return item.Added.Format("2006-01-02") })
+		row("ISBN", func(item reading.ItemDTO) string { return item.ISBN })
+		row("URL", func(item reading.ItemDTO) string { return item.URL })
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("%d groups of duplicates. Merge them all into their oldest item with --merge,\n", len(groups))
+	fmt.Println("or some of them with: gitlife reading merge <id> <duplicate-id>...")
+	return nil
+}
+
+func runMerge(cmd *cobra.Command, args []string) error {
+	item, err := service.Merge(reading.MergeCommand{IDs: args})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Merged %d items into %s: %s\n", len(args)-1, item.ID, item.Title)
+	return nil
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+
+	items, err := service.Export(listQuery(cmd, args))
+	if err != nil {
+		return err
+	}
+
+	var content bytes.Buffer
+	if err := exchange.Export(&content, format, items); err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+
+	if output == "" {
+		_, err := os.Stdout.Write(content.Bytes())
+		return err
+	}
+	if err := os.WriteFile(output, content.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d items to %s\n", len(items), output)
+	return nil
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	query := reading.StatsQuery{}
+	query.From, _ = cmd.Flags().GetString("from")
+	query.To, _ = cmd.Flags().GetString("to")
+	query.GroupBy, _ = cmd.Flags().GetString("group-by")
+
+	svc, err := serviceAt(cmd)
+	if err != nil {
+		return err
+	}
+	stats, err := svc.Stats(query)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Items: %d (to-read %d, reading %d, paused %d, done %d, abandoned %d)\n",
+		stats.Total, stats.ToRead, stats.Reading, stats.Paused, stats.Finished, stats.Abandoned)
+	fmt.Printf("Finished: %d items, %d pages\n", stats.ItemsFinished, stats.PagesFinished)
+	if stats.AverageRating > 0 {
+		fmt.Printf("Average rating: %.2f\n", stats.AverageRating)
+	}
+	if stats.AverageDaysToFinish > 0 {
+		fmt.Printf("Average days to finish: %.1f\n", stats.AverageDaysToFinish)
+	}
+	fmt.Printf("Pace (last %d days): %.2f items/month, %.2f pages/day, %.1f min/day\n",
+		stats.Pace.WindowDays, stats.Pace.ItemsPerMonth, stats.Pace.PagesPerDay, stats.Pace.MinutesPerDay)
+
+	if len(stats.Periods) > 0 {
+		counts := []int{}
+		for _, period := range stats.Periods {
+			counts = append(counts, period.Items)
+		}
+		fmt.Printf("\nFinished per %s: %s\n\n", stats.GroupBy, sparkline(counts))
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PERIOD\tITEMS\tPAGES")
+		for _, period := range stats.Periods {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", period.Period, period.Items, period.Pages)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(stats.RatingByType) > 0 || len(stats.RatingByTag) > 0 {
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RATING BY\tAVERAGE\tITEMS")
+		for _, rating := range stats.RatingByType {
+			fmt.Fprintf(w, "%s\t%.2f\t%d\n", rating.Key, rating.Average, rating.Count)
+		}
+		for _, rating := range stats.RatingByTag {
+			fmt.Fprintf(w, "#%s\t%.2f\t%d\n", rating.Key, rating.Average, rating.Count)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(stats.TopAuthors) > 0 {
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "AUTHOR\tFINISHED\tRATING")
+		for _, author := range stats.TopAuthors {
+			rating := ""
+			if author.AverageRating > 0 {
+				rating = fmt.Sprintf("%.2f", author.AverageRating)
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\n", truncate(author.Author, 30), author.Finished, rating)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sparkline draws values as a row of block characters scaled to the maximum.
+func sparkline(values []int) string {
+	blocks := []rune("▁▂▃▄▅▆▇█")
+
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	line := make([]rune, len(values))
+	for i, v := range values {
+		if max == 0 {
+			line[i] = blocks[0]
+			continue
+		}
+		line[i] = blocks[v*(len(blocks)-1)/max]
+	}
+	return string(line)
+}
+
+func runPause(cmd *cobra.Command, args []string) error {
+	if err := service.PauseReading(args[0]); err != nil {
+		return err
+	}
+
+	fmt.Printf("Paused: %s\n", args[0])
+	return nil
+}
+
+func runResume(cmd *cobra.Command, args []string) error {
+	if err := service.ResumeReading(args[0]); err != nil {
+		return err
+	}
+
+	fmt.Printf("Resumed: %s\n", args[0])
+	return nil
+}
+
+func runAbandon(cmd *cobra.Command, args []string) error {
+	reason, _ := cmd.Flags().GetString("reason")
+
+	command := reading.AbandonItemCommand{
+		ItemID: args[0],
+		Reason: reason,
+	}
+
+	if err := service.AbandonReading(command); err != nil {
+		return err
+	}
+
+	fmt.Printf("Abandoned: %s\n", args[0])
+	return nil
+}
+
+func runReread(cmd *cobra.Command, args []string) error {
+	if err := service.RereadItem(args[0]); err != nil {
+		return err
+	}
+
+	fmt.Printf("Reading again: %s\n", args[0])
 	return nil
 }
 
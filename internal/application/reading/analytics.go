@@ -0,0 +1,405 @@
+package reading
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	querylang "github.com/wguilherme/gitlife/internal/application/reading/query"
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+)
+
+const (
+	GroupByMonth = "month"
+	GroupByYear  = "year"
+
+	// paceWindow is how far back the current pace looks.
+	paceWindow = 90
+	topAuthors = 10
+)
+
+// StatsQuery limits the analytics to reads finished between From and To
+// (2025, 2025-03 or 2025-03-14, both inclusive) and groups them by month
+// or year.
+type StatsQuery struct {
+	From    string
+	To      string
+	GroupBy string
+}
+
+type StatsDTO struct {
+	Total     int `json:"total"`
+	ToRead    int `json:"to_read"`
+	Reading   int `json:"reading"`
+	Paused    int `json:"paused"`
+	Finished  int `json:"finished"`
+	Abandoned int `json:"abandoned"`
+
+	From    *time.Time `json:"from,omitempty"`
+	To      *time.Time `json:"to,omitempty"`
+	GroupBy string     `json:"group_by"`
+
+	ItemsFinished       int           `json:"items_finished"`
+	PagesFinished       int           `json:"pages_finished"`
+	Periods             []PeriodStats `json:"periods"`
+	AverageRating       float64       `json:"average_rating"`
+	RatingByType        []RatingStats `json:"rating_by_type"`
+	RatingByTag         []RatingStats `json:"rating_by_tag"`
+	AverageDaysToFinish float64       `json:"average_days_to_finish"`
+	Pace                PaceStats     `json:"pace"`
+	TopAuthors          []AuthorStats `json:"top_authors"`
+}
+
+// PeriodStats counts what was finished in one month ("2025-03") or year.
+type PeriodStats struct {
+	Period string `json:"period"`
+	Items  int    `json:"items"`
+	Pages  int    `json:"pages"`
+}
+
+type RatingStats struct {
+	Key     string  `json:"key"`
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+// PaceStats is the rate of the last days before the end of the range.
+type PaceStats struct {
+	WindowDays    int     `json:"window_days"`
+	ItemsPerMonth float64 `json:"items_per_month"`
+	PagesPerDay   float64 `json:"pages_per_day"`
+	MinutesPerDay float64 `json:"minutes_per_day"`
+}
+
+type AuthorStats struct {
+	Author        string  `json:"author"`
+	Finished      int     `json:"finished"`
+	AverageRating float64 `json:"average_rating,omitempty"`
+}
+
+func (s *Service) Stats(query StatsQuery) (*StatsDTO, error) {
+	groupBy := query.GroupBy
+	if groupBy == "" {
+		groupBy = GroupByMonth
+	}
+	if groupBy != GroupByMonth && groupBy != GroupByYear {
+		return nil, fmt.Errorf("invalid group_by: %s", query.GroupBy)
+	}
+
+	stats := &StatsDTO{GroupBy: groupBy}
+
+	var from, to time.Time
+	if query.From != "" {
+		start, _, err := querylang.ParsePeriod(query.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from: %s", query.From)
+		}
+		from = start
+		stats.From = &start
+	}
+	if query.To != "" {
+		_, end, err := querylang.ParsePeriod(query.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to: %s", query.To)
+		}
+		to = end
+		last := end.AddDate(0, 0, -1)
+		stats.To = &last
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	items, err := s.repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute stats: %w", err)
+	}
+
+	finished := []finishedRead{}
+	for _, item := range items {
+		stats.Total++
+		switch item.Status {
+		case reading.StatusToRead:
+			stats.ToRead++
+		case reading.StatusReading:
+			stats.Reading++
+		case reading.StatusPaused:
+			stats.Paused++
+		case reading.StatusDone:
+			stats.Finished++
+		case reading.StatusAbandoned:
+			stats.Abandoned++
+		}
+
+		for _, read := range item.ReadThroughs() {
+			if read.Finished == nil {
+				continue
+			}
+			at := *read.Finished
+			if (!from.IsZero() && at.Before(from)) || (!to.IsZero() && !at.Before(to)) {
+				continue
+			}
+			finished = append(finished, finishedRead{item: item, read: read})
+		}
+	}
+
+	// A snapshot of the past is measured up to when it was taken
+	end := time.Now()
+	if snapshot, ok := s.repo.(reading.SnapshotRepository); ok {
+		end = snapshot.Time()
+	}
+	if !to.IsZero() && to.Before(end) {
+		end = to
+	}
+
+	stats.ItemsFinished = len(finished)
+	stats.Periods = finishedPeriods(finished, groupBy, from, end)
+	for _, period := range stats.Periods {
+		stats.PagesFinished += period.Pages
+	}
+	stats.AverageRating, stats.RatingByType, stats.RatingByTag = ratingStats(finished)
+	stats.AverageDaysToFinish = averageDaysToFinish(finished)
+	stats.Pace = pace(items, end)
+	stats.TopAuthors = authorStats(finished)
+
+	return stats, nil
+}
+
+// finishedRead is one completed read of an item. Every read counts in the
+// analytics, so a re-read item counts once per time it was finished.
+type finishedRead struct {
+	item *reading.Item
+	read reading.ReadThrough
+}
+
+// rating returns the rating given to the read, or 0 when it has none.
+func (f finishedRead) rating() int {
+	if f.read.Rating == nil {
+		return 0
+	}
+	return f.read.Rating.Value()
+}
+
+// finishedPeriods buckets finished reads by period, including the empty
+// periods in between so the series can be charted as is.
+func finishedPeriods(reads []finishedRead, groupBy string, from, end time.Time) []PeriodStats {
+	layout, step := "2006-01", func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	if groupBy == GroupByYear {
+		layout, step = "2006", func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }
+	}
+
+	truncate := func(t time.Time) time.Time {
+		if groupBy == GroupByYear {
+			return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		}
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	buckets := make(map[string]*PeriodStats)
+	first, last := from, end.Add(-time.Nanosecond)
+	for _, finished := range reads {
+		at := *finished.read.Finished
+		key := at.Format(layout)
+		if buckets[key] == nil {
+			buckets[key] = &PeriodStats{Period: key}
+		}
+		buckets[key].Items++
+		buckets[key].Pages += pages(finished.item)
+
+		if first.IsZero() || at.Before(first) {
+			first = at
+		}
+		if at.After(last) {
+			last = at
+		}
+	}
+
+	periods := []PeriodStats{}
+	if first.IsZero() {
+		return periods
+	}
+	for t := truncate(first); !t.After(truncate(last)); t = step(t) {
+		key := t.Format(layout)
+		if bucket, ok := buckets[key]; ok {
+			periods = append(periods, *bucket)
+		} else {
+			periods = append(periods, PeriodStats{Period: key})
+		}
+	}
+	return periods
+}
+
+func ratingStats(reads []finishedRead) (float64, []RatingStats, []RatingStats) {
+	var all average
+	byType := make(map[string]*average)
+	byTag := make(map[string]*average)
+
+	add := func(groups map[string]*average, key string, value int) {
+		if groups[key] == nil {
+			groups[key] = &average{}
+		}
+		groups[key].add(float64(value))
+	}
+
+	for _, finished := range reads {
+		value := finished.rating()
+		if value == 0 {
+			continue
+		}
+		all.add(float64(value))
+		add(byType, string(finished.item.Type), value)
+		for _, tag := range finished.item.Tags {
+			add(byTag, string(tag), value)
+		}
+	}
+
+	return all.value(), ratingList(byType), ratingList(byTag)
+}
+
+func ratingList(groups map[string]*average) []RatingStats {
+	list := []RatingStats{}
+	for key, avg := range groups {
+		list = append(list, RatingStats{Key: key, Average: avg.value(), Count: avg.count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Average != list[j].Average {
+			return list[i].Average > list[j].Average
+		}
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Key < list[j].Key
+	})
+	return list
+}
+
+func averageDaysToFinish(reads []finishedRead) float64 {
+	var avg average
+	for _, finished := range reads {
+		if finished.read.Started == nil {
+			continue
+		}
+		days := finished.read.Finished.Sub(*finished.read.Started).Hours() / 24
+		if days >= 0 {
+			avg.add(days)
+		}
+	}
+	return avg.value()
+}
+
+// pace measures the paceWindow days before end. Pages come from the
+// sessions logged in the window; reads finished without any session count
+// with all their pages.
+func pace(items []*reading.Item, end time.Time) PaceStats {
+	start := end.AddDate(0, 0, -paceWindow)
+	inWindow := func(t time.Time) bool { return !t.Before(start) && t.Before(end) }
+
+	count, pageCount, minutes := 0, 0, 0
+	for _, item := range items {
+		for _, session := range item.Sessions {
+			if !inWindow(session.Date) {
+				continue
+			}
+			if item.Type.ProgressUnit() == reading.UnitPages {
+				pageCount += session.Pages()
+			}
+			minutes += session.Minutes
+		}
+
+		for _, read := range item.ReadThroughs() {
+			if read.Finished == nil || !inWindow(*read.Finished) {
+				continue
+			}
+			count++
+			if len(item.Sessions) == 0 {
+				pageCount += pages(item)
+			}
+		}
+	}
+
+	return PaceStats{
+		WindowDays:    paceWindow,
+		ItemsPerMonth: round(float64(count) / paceWindow * 30),
+		PagesPerDay:   round(float64(pageCount) / paceWindow),
+		MinutesPerDay: round(float64(minutes) / paceWindow),
+	}
+}
+
+func authorStats(reads []finishedRead) []AuthorStats {
+	type authorTotals struct {
+		finished int
+		rating   average
+	}
+
+	totals := make(map[string]*authorTotals)
+	for _, finished := range reads {
+		author := string(finished.item.Author)
+		if author == "" || author == "Unknown" {
+			continue
+		}
+		if totals[author] == nil {
+			totals[author] = &authorTotals{}
+		}
+		totals[author].finished++
+		if value := finished.rating(); value > 0 {
+			totals[author].rating.add(float64(value))
+		}
+	}
+
+	authors := []AuthorStats{}
+	for author, total := range totals {
+		authors = append(authors, AuthorStats{
+			Author:        author,
+			Finished:      total.finished,
+			AverageRating: total.rating.value(),
+		})
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Finished != authors[j].Finished {
+			return authors[i].Finished > authors[j].Finished
+		}
+		if authors[i].AverageRating != authors[j].AverageRating {
+			return authors[i].AverageRating > authors[j].AverageRating
+		}
+		return authors[i].Author < authors[j].Author
+	})
+
+	if len(authors) > topAuthors {
+		authors = authors[:topAuthors]
+	}
+	return authors
+}
+
+// pages is what a read of an item counts towards the pages read. Videos and
+// courses are not counted in pages.
+func pages(item *reading.Item) int {
+	if item.Progress == nil || item.Type.ProgressUnit() != reading.UnitPages {
+		return 0
+	}
+	if item.Progress.TotalPages > 0 {
+		return item.Progress.TotalPages
+	}
+	return item.Progress.CurrentPage
+}
+
+type average struct {
+	sum   float64
+	count int
+}
+
+func (a *average) add(value float64) {
+	a.sum += value
+	a.count++
+}
+
+func (a *average) value() float64 {
+	if a.count == 0 {
+		return 0
+	}
+	return round(a.sum / float64(a.count))
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,234 @@
+package reading
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+)
+
+// DuplicateGroup is a set of items that are probably the same, oldest
+// first, with what they have in common (isbn, doi, url or title).
+type DuplicateGroup struct {
+	Items   []ItemDTO `json:"items"`
+	Reasons []string  `json:"reasons"`
+}
+
+// MergeCommand merges items into the first one.
+type MergeCommand struct {
+	IDs []string
+}
+
+// Duplicates finds the items that are probably the same: same ISBN, DOI or
+// URL, or the same type and title (with or without subtitle) by a similar
+// author, so "Robert Martin" and "Robert C. Martin" are grouped.
+func (s *Service) Duplicates() ([]DuplicateGroup, error) {
+	items, err := s.repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load items: %w", err)
+	}
+
+	groups := []DuplicateGroup{}
+	for _, group := range duplicateGroups(items) {
+		dto := DuplicateGroup{Reasons: group.reasons}
+		for _, item := range group.items {
+			dto.Items = append(dto.Items, ToDTO(item))
+		}
+		groups = append(groups, dto)
+	}
+	return groups, nil
+}
+
+// MergeDuplicates merges every group found by Duplicates into its oldest
+// item in a single write and returns the merged items.
+func (s *Service) MergeDuplicates() ([]ItemDTO, error) {
+	items, err := s.repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load items: %w", err)
+	}
+
+	merged := []ItemDTO{}
+	touched := []*reading.Item{}
+	removed := make(map[reading.ItemID]bool)
+	for _, group := range duplicateGroups(items) {
+		kept := group.items[0]
+		for _, duplicate := range group.items[1:] {
+			kept.Merge(duplicate)
+			removed[duplicate.ID] = true
+		}
+		merged = append(merged, ToDTO(kept))
+		touched = append(touched, group.items...)
+	}
+	if len(merged) == 0 {
+		return merged, nil
+	}
+
+	if err := s.describe("merge", touched...).ReplaceAll(withoutItems(items, removed)); err != nil {
+		return nil, fmt.Errorf("failed to save merged items: %w", err)
+	}
+	return merged, nil
+}
+
+// Merge folds the other items of the command into the first one and
+// removes them, in a single write.
+func (s *Service) Merge(cmd MergeCommand) (*ItemDTO, error) {
+	if len(cmd.IDs) < 2 {
+		return nil, errors.New("at least two items are needed to merge")
+	}
+
+	items, err := s.repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load items: %w", err)
+	}
+
+	var kept *reading.Item
+	touched := []*reading.Item{}
+	removed := make(map[reading.ItemID]bool)
+	for _, id := range cmd.IDs {
+		item, err := resolveItem(items, id)
+		if err != nil {
+			return nil, err
+		}
+		if item == kept || removed[item.ID] {
+			return nil, fmt.Errorf("%s (%s) is listed twice", item.ID, item.Title)
+		}
+		touched = append(touched, item)
+		if kept == nil {
+			kept = item
+			continue
+		}
+		kept.Merge(item)
+		removed[item.ID] = true
+	}
+
+	if err := s.describe("merge", touched...).ReplaceAll(withoutItems(items, removed)); err != nil {
+		return nil, fmt.Errorf("failed to save merged items: %w", err)
+	}
+
+	dto := ToDTO(kept)
+	return &dto, nil
+}
+
+type duplicateGroup struct {
+	items   []*reading.Item
+	reasons []string
+}
+
+// duplicateGroups groups items that are duplicates of each other, directly
+// or through another item. Groups and their items are ordered by the date
+// the items were added.
+func duplicateGroups(items []*reading.Item) []duplicateGroup {
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+
+	reasons := make(map[int][]string)
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			reason := duplicateReason(items[i], items[j])
+			if reason == "" {
+				continue
+			}
+			a, b := root(i), root(j)
+			if a != b {
+				parent[b] = a
+				reasons[a] = appendMissing(reasons[a], reasons[b]...)
+			}
+			reasons[a] = appendMissing(reasons[a], reason)
+		}
+	}
+
+	members := make(map[int][]*reading.Item)
+	for i, item := range items {
+		members[root(i)] = append(members[root(i)], item)
+	}
+
+	groups := []duplicateGroup{}
+	for i, group := range members {
+		if len(group) < 2 {
+			continue
+		}
+		sort.SliceStable(group, func(a, b int) bool {
+			return group[a].Metadata.Added.Before(group[b].Metadata.Added)
+		})
+		groups = append(groups, duplicateGroup{items: group, reasons: reasons[i]})
+	}
+	sort.Slice(groups, func(a, b int) bool {
+		return groups[a].items[0].Metadata.Added.Before(groups[b].items[0].Metadata.Added)
+	})
+	return groups
+}
+
+// duplicateReason tells why two items look like the same one, or returns
+// "" when they do not. Two different ISBNs, DOIs or URLs rule out a title
+// match, since editions and links often share titles.
+func duplicateReason(a, b *reading.Item) string {
+	same := func(x, y string) bool { return x != "" && x == y }
+	differ := func(x, y string) bool { return x != "" && y != "" && x != y }
+
+	isbnA, isbnB := normalizeISBN(a.Metadata.ISBN), normalizeISBN(b.Metadata.ISBN)
+	doiA, doiB := strings.ToLower(a.Metadata.DOI), strings.ToLower(b.Metadata.DOI)
+	urlA, urlB := normalizeURL(a.Metadata.URL), normalizeURL(b.Metadata.URL)
+
+	switch {
+	case same(isbnA, isbnB):
+		return "isbn"
+	case same(doiA, doiB):
+		return "doi"
+	case same(urlA, urlB):
+		return "url"
+	case differ(isbnA, isbnB) || differ(doiA, doiB) || differ(urlA, urlB) || a.Type != b.Type:
+		return ""
+	}
+
+	titleA, titleB := normalize(string(a.Title)), normalize(string(b.Title))
+	shortA, shortB := normalize(mainTitle(string(a.Title))), normalize(mainTitle(string(b.Title)))
+	if titleA != titleB && shortA != titleB && titleA != shortB && shortA != shortB {
+		return ""
+	}
+	if !similarAuthors(string(a.Author), string(b.Author)) {
+		return ""
+	}
+	return "title"
+}
+
+// similarAuthors compares the first author of each item by last name and
+// first initial, so "Robert Martin", "Robert C. Martin" and "Martin, R. C."
+// are alike. An unknown author is like any other.
+func similarAuthors(a, b string) bool {
+	namesA, namesB := authorNames(a), authorNames(b)
+	if len(namesA) == 0 || len(namesB) == 0 {
+		return true
+	}
+	return namesA[len(namesA)-1] == namesB[len(namesB)-1] && namesA[0][0] == namesB[0][0]
+}
+
+// authorNames returns the normalized names of the first author, given
+// names first.
+func authorNames(author string) []string {
+	if author == "Unknown" {
+		return nil
+	}
+	author = strings.TrimSuffix(strings.TrimSpace(author), " et al.")
+	author, _, _ = strings.Cut(author, " and ")
+	if family, given, found := strings.Cut(author, ","); found {
+		author = given + " " + family
+	}
+	return strings.Fields(normalize(author))
+}
+
+func withoutItems(items []*reading.Item, removed map[reading.ItemID]bool) []*reading.Item {
+	return filterItems(items, func(item *reading.Item) bool {
+		return !removed[item.ID]
+	})
+}
@@ -6,19 +6,42 @@ import (
 	"github.com/wguilherme/gitlife/internal/domain/reading"
 )
 
+// AddItemCommand creates an item. TotalPages is its length in the progress
+// unit of its type (pages, minutes for videos, lessons for courses).
 type AddItemCommand struct {
-	Title    string
-	Author   string
-	Type     string
-	Priority string
-	Tags     []string
-	URL      string
+	Title      string
+	Author     string
+	Type       string
+	Priority   string
+	Tags       []string
+	URL        string
+	TotalPages int
 }
 
+// UpdateItemCommand carries a partial update: nil fields are left untouched.
+type UpdateItemCommand struct {
+	ItemID     string
+	Title      *string
+	Author     *string
+	Type       *string
+	Priority   *string
+	Tags       *[]string
+	URL        *string
+	Notes      *string
+	TotalPages *int
+}
+
+// UpdateProgressCommand updates the progress of an item and logs it as a
+// reading session. CurrentPage is counted in the progress unit of the item
+// and Unit, when set, must match it. Percentage may be left nil when the
+// total is known; it is then derived from CurrentPage. Minutes is the
+// length of the session, 0 when unknown.
 type UpdateProgressCommand struct {
 	ItemID      string
-	Percentage  int
+	Percentage  *int
 	CurrentPage int
+	Unit        string
+	Minutes     int
 }
 
 type FinishItemCommand struct {
@@ -27,6 +50,42 @@ type FinishItemCommand struct {
 	Review string
 }
 
+// AddHighlightCommand saves a passage of an item. Page is counted in the
+// progress unit of the item, 0 when unknown.
+type AddHighlightCommand struct {
+	ItemID string
+	Text   string
+	Page   int
+	Note   string
+}
+
+type AbandonItemCommand struct {
+	ItemID string
+	Reason string
+}
+
+// ListQuery filters, sorts and paginates the reading list. Sort is a sort
+// key (added, started, finished, priority, rating, title), prefixed with
+// "-" for descending order. Expression is written in the query language
+// (see package query) and combined with the other filters.
+type ListQuery struct {
+	Expression string
+	Status     string
+	Tags       []string
+	Priority   string
+	Type       string
+	Search     string
+	Sort       string
+	Limit      int
+	Offset     int
+}
+
+type ListResult struct {
+	Items []ItemDTO `json:"items"`
+	Count int       `json:"count"`
+	Total int       `json:"total"`
+}
+
 type ItemDTO struct {
 	ID          string     `json:"id"`
 	Title       string     `json:"title"`
@@ -36,32 +95,64 @@ type ItemDTO struct {
 	Priority    string     `json:"priority"`
 	Tags        []string   `json:"tags"`
 	Progress    int        `json:"progress,omitempty"`
+	Unit        string     `json:"unit"`
 	CurrentPage int        `json:"current_page,omitempty"`
 	TotalPages  int        `json:"total_pages,omitempty"`
 	Rating      int        `json:"rating,omitempty"`
+	Reads       int        `json:"reads,omitempty"`
+	Highlights  int        `json:"highlights,omitempty"`
 	URL         string     `json:"url,omitempty"`
+	ISBN        string     `json:"isbn,omitempty"`
+	DOI         string     `json:"doi,omitempty"`
+	Venue       string     `json:"venue,omitempty"`
+	Year        int        `json:"year,omitempty"`
+	Authors     []string   `json:"authors,omitempty"`
 	Notes       string     `json:"notes,omitempty"`
 	Review      string     `json:"review,omitempty"`
 	Added       time.Time  `json:"added"`
 	Started     *time.Time `json:"started,omitempty"`
 	Finished    *time.Time `json:"finished,omitempty"`
+	Paused      *time.Time `json:"paused,omitempty"`
+	Abandoned   *time.Time `json:"abandoned,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+}
+
+// SessionDTO is a reading session. Pages are counted in Unit, the progress
+// unit of the item.
+type SessionDTO struct {
+	Date       time.Time `json:"date"`
+	Unit       string    `json:"unit"`
+	FromPage   int       `json:"from_page,omitempty"`
+	ToPage     int       `json:"to_page,omitempty"`
+	Pages      int       `json:"pages,omitempty"`
+	Percentage int       `json:"percentage"`
+	Minutes    int       `json:"minutes,omitempty"`
 }
 
 func ToDTO(item *reading.Item) ItemDTO {
 	dto := ItemDTO{
-		ID:       string(item.ID),
-		Title:    string(item.Title),
-		Author:   string(item.Author),
-		Type:     string(item.Type),
-		Status:   string(item.Status),
-		Priority: string(item.Priority),
-		Tags:     []string{},
-		URL:      item.Metadata.URL,
-		Notes:    item.Metadata.Notes,
-		Review:   item.Metadata.Review,
-		Added:    item.Metadata.Added,
-		Started:  item.Metadata.Started,
-		Finished: item.Metadata.Finished,
+		ID:        string(item.ID),
+		Title:     string(item.Title),
+		Author:    string(item.Author),
+		Type:      string(item.Type),
+		Status:    string(item.Status),
+		Priority:  string(item.Priority),
+		Unit:      string(item.Type.ProgressUnit()),
+		Tags:      []string{},
+		URL:       item.Metadata.URL,
+		ISBN:      item.Metadata.ISBN,
+		DOI:       item.Metadata.DOI,
+		Venue:     item.Metadata.Venue,
+		Year:      item.Metadata.Year,
+		Authors:   item.Metadata.Authors,
+		Notes:     item.Metadata.Notes,
+		Review:    item.Metadata.Review,
+		Added:     item.Metadata.Added,
+		Started:   item.Metadata.Started,
+		Finished:  item.Metadata.Finished,
+		Paused:    item.Metadata.Paused,
+		Abandoned: item.Metadata.Abandoned,
+		Reason:    item.Metadata.Reason,
 	}
 
 	for _, tag := range item.Tags {
@@ -74,10 +165,37 @@ func ToDTO(item *reading.Item) ItemDTO {
 		dto.TotalPages = item.Progress.TotalPages
 	}
 
-	if item.Rating != nil {
-		dto.Rating = item.Rating.Value()
+	if rating := item.LatestRating(); rating != nil {
+		dto.Rating = rating.Value()
 	}
 
+	dto.Reads = len(item.ReadThroughs())
+	dto.Highlights = len(item.Highlights)
+
+	return dto
+}
+
+// ExportItemDTO is an item as the JSON, NDJSON and YAML exports write it:
+// the fields of ItemDTO with the reads, highlights and sessions in full
+// instead of counted, so an export can be imported back as it was.
+type ExportItemDTO struct {
+	ItemDTO
+	Reads      []ReadThroughDTO `json:"reads,omitempty"`
+	Highlights []HighlightDTO   `json:"highlights,omitempty"`
+	Sessions   []SessionDTO     `json:"sessions,omitempty"`
+}
+
+func ToExportDTO(item *reading.Item) ExportItemDTO {
+	dto := ExportItemDTO{ItemDTO: ToDTO(item)}
+	if reads := item.ReadThroughs(); len(reads) > 0 {
+		dto.Reads = ToReadThroughDTOList(reads)
+	}
+	if len(item.Highlights) > 0 {
+		dto.Highlights = ToHighlightDTOList(item)
+	}
+	if len(item.Sessions) > 0 {
+		dto.Sessions = ToSessionDTOList(item)
+	}
 	return dto
 }
 
@@ -88,3 +206,71 @@ func ToDTOList(items []*reading.Item) []ItemDTO {
 	}
 	return dtos
 }
+
+// ReadThroughDTO is one completed read of an item.
+type ReadThroughDTO struct {
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+	Rating   int        `json:"rating,omitempty"`
+	Review   string     `json:"review,omitempty"`
+}
+
+func ToReadThroughDTOList(reads []reading.ReadThrough) []ReadThroughDTO {
+	dtos := []ReadThroughDTO{}
+	for _, read := range reads {
+		dto := ReadThroughDTO{
+			Started:  read.Started,
+			Finished: read.Finished,
+			Review:   read.Review,
+		}
+		if read.Rating != nil {
+			dto.Rating = read.Rating.Value()
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos
+}
+
+// HighlightDTO is a saved passage. Page is counted in Unit, the progress
+// unit of the item.
+type HighlightDTO struct {
+	Added time.Time `json:"added"`
+	Unit  string    `json:"unit"`
+	Text  string    `json:"text"`
+	Page  int       `json:"page,omitempty"`
+	Note  string    `json:"note,omitempty"`
+}
+
+func ToHighlightDTO(item *reading.Item, highlight reading.Highlight) HighlightDTO {
+	return HighlightDTO{
+		Added: highlight.Added,
+		Unit:  string(item.Type.ProgressUnit()),
+		Text:  highlight.Text,
+		Page:  highlight.Page,
+		Note:  highlight.Note,
+	}
+}
+
+func ToHighlightDTOList(item *reading.Item) []HighlightDTO {
+	dtos := []HighlightDTO{}
+	for _, highlight := range item.Highlights {
+		dtos = append(dtos, ToHighlightDTO(item, highlight))
+	}
+	return dtos
+}
+
+func ToSessionDTOList(item *reading.Item) []SessionDTO {
+	dtos := []SessionDTO{}
+	for _, session := range item.Sessions {
+		dtos = append(dtos, SessionDTO{
+			Date:       session.Date,
+			Unit:       string(item.Type.ProgressUnit()),
+			FromPage:   session.FromPage,
+			ToPage:     session.ToPage,
+			Pages:      session.Pages(),
+			Percentage: session.Percentage,
+			Minutes:    session.Minutes,
+		})
+	}
+	return dtos
+}
@@ -0,0 +1,69 @@
+package reading
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+)
+
+func (s *Service) AddHighlight(cmd AddHighlightCommand) (*HighlightDTO, error) {
+	item, err := s.findItem(cmd.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	highlight, err := item.AddHighlight(time.Now(), cmd.Text, cmd.Page, cmd.Note)
+	if err != nil {
+		return nil, progressError(item, err)
+	}
+
+	if err := s.describe("highlight", item).Update(item); err != nil {
+		return nil, err
+	}
+
+	dto := ToHighlightDTO(item, highlight)
+	return &dto, nil
+}
+
+// Highlights returns the passages saved from an item, oldest first.
+func (s *Service) Highlights(id string) ([]HighlightDTO, error) {
+	item, err := s.findItem(id)
+	if err != nil {
+		return nil, err
+	}
+	return ToHighlightDTOList(item), nil
+}
+
+// HighlightsMarkdown renders the highlights of an item as a Markdown
+// document: one blockquote per passage, followed by its note.
+func (s *Service) HighlightsMarkdown(id string) (string, error) {
+	item, err := s.findItem(id)
+	if err != nil {
+		return "", err
+	}
+	return highlightsMarkdown(item), nil
+}
+
+func highlightsMarkdown(item *reading.Item) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n", item.Title)
+	if item.Author != "" && item.Author != "Unknown" {
+		fmt.Fprintf(&b, "\n*%s*\n", item.Author)
+	}
+
+	unit := item.Type.ProgressUnit()
+	for _, highlight := range item.Highlights {
+		fmt.Fprintf(&b, "\n> %s\n", highlight.Text)
+		if highlight.Page > 0 {
+			fmt.Fprintf(&b, ">\n> — %s %d\n", unit.Singular(), highlight.Page)
+		}
+		if highlight.Note != "" {
+			fmt.Fprintf(&b, "\n%s\n", highlight.Note)
+		}
+	}
+
+	return b.String()
+}
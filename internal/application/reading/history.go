@@ -0,0 +1,164 @@
+package reading
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+)
+
+// HistoryEntryDTO is a commit that changed an item, with what it changed.
+type HistoryEntryDTO struct {
+	Commit  string           `json:"commit"`
+	Author  string           `json:"author"`
+	Date    time.Time        `json:"date"`
+	Message string           `json:"message"`
+	Changes []FieldChangeDTO `json:"changes"`
+}
+
+// FieldChangeDTO is a property an item changed in a commit. From is empty
+// when the property was set and To when it was cleared.
+type FieldChangeDTO struct {
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// historyFields are the properties compared between revisions, in the
+// order their changes are listed.
+var historyFields = []struct {
+	name  string
+	value func(item *reading.Item) string
+}{
+	{"status", func(item *reading.Item) string { return string(item.Status) }},
+	{"progress", describeProgress},
+	{"rating", func(item *reading.Item) string {
+		if item.Rating == nil || item.Rating.Value() == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d/5", item.Rating.Value())
+	}},
+	{"length", func(item *reading.Item) string {
+		if item.Progress == nil || item.Progress.TotalPages == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d %s", item.Progress.TotalPages, item.Type.ProgressUnit())
+	}},
+	{"reads", func(item *reading.Item) string { return count(len(item.History)) }},
+	{"title", func(item *reading.Item) string { return string(item.Title) }},
+	{"author", func(item *reading.Item) string { return string(item.Author) }},
+	{"type", func(item *reading.Item) string { return string(item.Type) }},
+	{"priority", func(item *reading.Item) string { return string(item.Priority) }},
+	{"tags", func(item *reading.Item) string {
+		tags := make([]string, len(item.Tags))
+		for i, tag := range item.Tags {
+			tags[i] = "#" + string(tag)
+		}
+		return strings.Join(tags, " ")
+	}},
+	{"reason", func(item *reading.Item) string { return item.Metadata.Reason }},
+	{"review", func(item *reading.Item) string { return item.Metadata.Review }},
+	{"notes", func(item *reading.Item) string { return item.Metadata.Notes }},
+	{"highlights", func(item *reading.Item) string { return count(len(item.Highlights)) }},
+	{"url", func(item *reading.Item) string { return item.Metadata.URL }},
+	{"isbn", func(item *reading.Item) string { return item.Metadata.ISBN }},
+	{"doi", func(item *reading.Item) string { return item.Metadata.DOI }},
+}
+
+// History rebuilds the timeline of an item from the commits of the vault,
+// oldest first: when it was added, each status change, progress jump and
+// rating or other property edit, with the commit that made it.
+func (s *Service) History(id string) ([]HistoryEntryDTO, error) {
+	item, err := s.findItem(id)
+	if err != nil {
+		return nil, err
+	}
+
+	history, ok := s.repo.(reading.HistoryRepository)
+	if !ok {
+		return nil, errors.New("item history needs git to be set up for the vault")
+	}
+	revisions, err := history.History(item.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []HistoryEntryDTO{}
+	var previous *reading.Item
+	for _, revision := range revisions {
+		changes := diffRevisions(previous, revision.Item)
+		previous = revision.Item
+		if len(changes) == 0 {
+			continue
+		}
+
+		hash := revision.Hash
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+		entries = append(entries, HistoryEntryDTO{
+			Commit:  hash,
+			Author:  revision.Author,
+			Date:    revision.Date,
+			Message: revision.Message,
+			Changes: changes,
+		})
+	}
+	return entries, nil
+}
+
+// diffRevisions lists what changed from one revision of an item to the
+// next; nil stands for an item that did not exist.
+func diffRevisions(before, after *reading.Item) []FieldChangeDTO {
+	switch {
+	case before == nil && after == nil:
+		return nil
+	case before == nil:
+		// An added item shows where it started: its status, progress and rating
+		changes := []FieldChangeDTO{{Field: "item", To: "added"}}
+		for _, field := range historyFields[:3] {
+			if value := field.value(after); value != "" {
+				changes = append(changes, FieldChangeDTO{Field: field.name, To: value})
+			}
+		}
+		return changes
+	case after == nil:
+		return []FieldChangeDTO{{Field: "item", To: "deleted"}}
+	}
+
+	changes := []FieldChangeDTO{}
+	for _, field := range historyFields {
+		from, to := field.value(before), field.value(after)
+		if from != to {
+			changes = append(changes, FieldChangeDTO{Field: field.name, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+func describeProgress(item *reading.Item) string {
+	progress := item.Progress
+	if progress == nil || (progress.Percentage == 0 && progress.CurrentPage == 0) {
+		return ""
+	}
+
+	unit := item.Type.ProgressUnit().Singular()
+	switch {
+	case progress.CurrentPage > 0 && progress.TotalPages > 0:
+		return fmt.Sprintf("%d%% (%s %d of %d)", progress.Percentage, unit, progress.CurrentPage, progress.TotalPages)
+	case progress.CurrentPage > 0:
+		return fmt.Sprintf("%d%% (%s %d)", progress.Percentage, unit, progress.CurrentPage)
+	default:
+		return fmt.Sprintf("%d%%", progress.Percentage)
+	}
+}
+
+func count(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
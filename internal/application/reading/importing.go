@@ -0,0 +1,528 @@
+package reading
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+)
+
+// ImportRecord is an item read from another application or from an
+// export. Records are matched against the vault by ID, then DOI, ISBN or
+// URL, then title (and author, when several items share the title); items
+// that are not found are created, keeping the ID when there is one. Empty
+// fields are left out.
+type ImportRecord struct {
+	ID          string
+	Title       string
+	Author      string
+	Type        string
+	ISBN        string
+	DOI         string
+	Venue       string
+	Year        int
+	Authors     []string
+	URL         string
+	Status      string
+	Priority    string
+	Tags        []string
+	Progress    int
+	CurrentPage int
+	TotalPages  int
+	Rating      int
+	Added       time.Time
+	Started     *time.Time
+	Finished    *time.Time
+	Paused      *time.Time
+	Abandoned   *time.Time
+	Reason      string
+	Review      string
+	Notes       string
+	Highlights  []ImportHighlight
+	// Reads are the earlier reads of an item that was read again, the
+	// current one left out.
+	Reads    []ReadThroughDTO
+	Sessions []SessionDTO
+}
+
+// ImportRecordFromDTO turns an exported item back into a record.
+func ImportRecordFromDTO(dto ExportItemDTO) ImportRecord {
+	record := ImportRecord{
+		ID:          dto.ID,
+		Title:       dto.Title,
+		Author:      dto.Author,
+		Type:        dto.Type,
+		ISBN:        dto.ISBN,
+		DOI:         dto.DOI,
+		Venue:       dto.Venue,
+		Year:        dto.Year,
+		Authors:     dto.Authors,
+		URL:         dto.URL,
+		Status:      dto.Status,
+		Priority:    dto.Priority,
+		Tags:        dto.Tags,
+		Progress:    dto.Progress,
+		CurrentPage: dto.CurrentPage,
+		TotalPages:  dto.TotalPages,
+		Rating:      dto.Rating,
+		Added:       dto.Added,
+		Started:     dto.Started,
+		Finished:    dto.Finished,
+		Paused:      dto.Paused,
+		Abandoned:   dto.Abandoned,
+		Reason:      dto.Reason,
+		Review:      dto.Review,
+		Notes:       dto.Notes,
+		Reads:       dto.Reads,
+		Sessions:    dto.Sessions,
+	}
+
+	// The last read of a finished item is its current one, held by the
+	// fields of the item
+	if dto.Status == string(reading.StatusDone) && len(record.Reads) > 0 {
+		record.Reads = record.Reads[:len(record.Reads)-1]
+	}
+	for _, highlight := range dto.Highlights {
+		record.Highlights = append(record.Highlights, ImportHighlight{
+			Added: highlight.Added,
+			Text:  highlight.Text,
+			Page:  highlight.Page,
+			Note:  highlight.Note,
+		})
+	}
+	return record
+}
+
+// ImportHighlight is a passage of an imported item. Added is the time it
+// was saved in the other application, zero when unknown.
+type ImportHighlight struct {
+	Added time.Time
+	Text  string
+	Page  int
+	Note  string
+}
+
+// ImportCommand imports records into the vault. With DryRun the report is
+// computed but nothing is written.
+type ImportCommand struct {
+	Records []ImportRecord
+	DryRun  bool
+}
+
+// ImportReport tells what an import changed in the vault.
+type ImportReport struct {
+	DryRun     bool           `json:"dry_run"`
+	Items      []ImportedItem `json:"items"`
+	Created    int            `json:"created"`
+	Updated    int            `json:"updated"`
+	Unchanged  int            `json:"unchanged"`
+	Highlights int            `json:"highlights"`
+	Duplicates int            `json:"duplicates"`
+}
+
+// ImportedItem is the outcome of an import for one item. Fields lists what
+// was filled in on an existing item; Duplicates counts the highlights that
+// were already saved and were skipped.
+type ImportedItem struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Status     string   `json:"status"`
+	Created    bool     `json:"created"`
+	Fields     []string `json:"fields,omitempty"`
+	Highlights int      `json:"highlights"`
+	Duplicates int      `json:"duplicates"`
+}
+
+// Import adds the records to the vault in a single write. Existing items
+// only get the fields they are missing, so importing the same records again
+// only adds what is new.
+func (s *Service) Import(cmd ImportCommand) (*ImportReport, error) {
+	items, err := s.repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load items: %w", err)
+	}
+
+	report := &ImportReport{DryRun: cmd.DryRun, Items: []ImportedItem{}}
+	outcomes := make(map[reading.ItemID]int)
+	imported := make(map[reading.ItemID]*reading.Item)
+
+	for _, record := range cmd.Records {
+		item := matchImport(items, record)
+		created := item == nil
+		if created {
+			item, err = newImportedItem(record)
+			if err != nil {
+				return nil, fmt.Errorf("failed to import %q: %w", record.Title, err)
+			}
+			items = append(items, item)
+		}
+
+		index, seen := outcomes[item.ID]
+		if !seen {
+			imported[item.ID] = item
+			index = len(report.Items)
+			outcomes[item.ID] = index
+			report.Items = append(report.Items, ImportedItem{
+				ID:      string(item.ID),
+				Title:   string(item.Title),
+				Created: created,
+			})
+		}
+		outcome := &report.Items[index]
+
+		fields, err := fillImported(item, record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import %q: %w", record.Title, err)
+		}
+		if !created {
+			outcome.Fields = appendMissing(outcome.Fields, fields...)
+		}
+
+		for _, highlight := range record.Highlights {
+			if item.HasHighlight(highlight.Text) {
+				outcome.Duplicates++
+				continue
+			}
+			if err := importHighlight(item, highlight); err != nil {
+				return nil, fmt.Errorf("failed to import highlight of %q: %w", item.Title, err)
+			}
+			outcome.Highlights++
+		}
+		outcome.Status = string(item.Status)
+	}
+
+	changed := []*reading.Item{}
+	for _, outcome := range report.Items {
+		switch {
+		case outcome.Created:
+			report.Created++
+			changed = append(changed, imported[reading.ItemID(outcome.ID)])
+		case len(outcome.Fields) > 0 || outcome.Highlights > 0:
+			report.Updated++
+			changed = append(changed, imported[reading.ItemID(outcome.ID)])
+		default:
+			report.Unchanged++
+		}
+		report.Highlights += outcome.Highlights
+		report.Duplicates += outcome.Duplicates
+	}
+
+	if cmd.DryRun {
+		return report, nil
+	}
+	if err := s.describe("import", changed...).ReplaceAll(items); err != nil {
+		return nil, fmt.Errorf("failed to save imported items: %w", err)
+	}
+	return report, nil
+}
+
+// matchImport finds the item a record refers to. Titles are compared with
+// and without their subtitle, so "Dune: Deluxe Edition" finds "Dune"; a
+// record with a URL never matches an item with another URL by title, since
+// links often share titles like "Home".
+func matchImport(items []*reading.Item, record ImportRecord) *reading.Item {
+	if record.ID != "" {
+		for _, item := range items {
+			if string(item.ID) == record.ID {
+				return item
+			}
+		}
+	}
+
+	if doi := strings.ToLower(record.DOI); doi != "" {
+		for _, item := range items {
+			if strings.ToLower(item.Metadata.DOI) == doi {
+				return item
+			}
+		}
+	}
+
+	if isbn := normalizeISBN(record.ISBN); isbn != "" {
+		for _, item := range items {
+			if normalizeISBN(item.Metadata.ISBN) == isbn {
+				return item
+			}
+		}
+	}
+
+	link := normalizeURL(record.URL)
+	if link != "" {
+		for _, item := range items {
+			if normalizeURL(item.Metadata.URL) == link {
+				return item
+			}
+		}
+	}
+
+	title := normalize(record.Title)
+	short := normalize(mainTitle(record.Title))
+
+	matches := filterItems(items, func(item *reading.Item) bool {
+		if link != "" && item.Metadata.URL != "" {
+			return false
+		}
+		candidate := normalize(string(item.Title))
+		return candidate == title || candidate == short || normalize(mainTitle(string(item.Title))) == short
+	})
+	if len(matches) == 0 {
+		return nil
+	}
+
+	author := normalize(record.Author)
+	if author != "" {
+		for _, item := range matches {
+			if normalize(string(item.Author)) == author {
+				return item
+			}
+		}
+	}
+	return matches[0]
+}
+
+// mainTitle drops the subtitle of a title.
+func mainTitle(title string) string {
+	main, _, _ := strings.Cut(title, ":")
+	return main
+}
+
+// normalizeISBN keeps the digits (and the X check digit) of an ISBN.
+func normalizeISBN(isbn string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9':
+			return r
+		case r == 'x' || r == 'X':
+			return 'X'
+		default:
+			return -1
+		}
+	}, isbn)
+}
+
+// normalizeURL reduces a link to what identifies the page: the scheme,
+// "www.", the fragment, tracking parameters and a trailing slash are
+// dropped and the host is lowercased.
+func normalizeURL(raw string) string {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || parsed.Host == "" {
+		return strings.TrimSpace(raw)
+	}
+
+	query := parsed.Query()
+	for key := range query {
+		if strings.HasPrefix(key, "utm_") {
+			query.Del(key)
+		}
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	normalized := host + strings.TrimSuffix(parsed.EscapedPath(), "/")
+	if encoded := query.Encode(); encoded != "" {
+		normalized += "?" + encoded
+	}
+	return normalized
+}
+
+func newImportedItem(record ImportRecord) (*reading.Item, error) {
+	title, err := reading.NewTitle(strings.TrimSpace(record.Title))
+	if err != nil {
+		return nil, err
+	}
+
+	author := reading.Author(strings.TrimSpace(record.Author))
+	if author == "" {
+		author = "Unknown"
+	}
+
+	itemType := reading.ItemType(record.Type)
+	if !itemType.IsValid() {
+		itemType = reading.TypeBook
+	}
+
+	item, err := reading.NewItem(title, author, itemType)
+	if err != nil {
+		return nil, err
+	}
+	if record.ID != "" {
+		item.ID = reading.ItemID(record.ID)
+	}
+	if !record.Added.IsZero() {
+		item.Metadata.Added = record.Added
+	}
+	if priority := reading.Priority(record.Priority); priority.IsValid() {
+		item.Priority = priority
+	}
+	return item, nil
+}
+
+// fillImported copies the fields of the record the item is missing and
+// returns their names. The status and progress are only taken over by
+// items that were not started yet.
+func fillImported(item *reading.Item, record ImportRecord) ([]string, error) {
+	fields := []string{}
+
+	if author := strings.TrimSpace(record.Author); author != "" && (item.Author == "" || item.Author == "Unknown") {
+		item.Author = reading.Author(author)
+		fields = append(fields, "author")
+	}
+	if record.ISBN != "" && item.Metadata.ISBN == "" {
+		item.Metadata.ISBN = record.ISBN
+		fields = append(fields, "isbn")
+	}
+	if record.DOI != "" && item.Metadata.DOI == "" {
+		item.Metadata.DOI = record.DOI
+		fields = append(fields, "doi")
+	}
+	if record.Venue != "" && item.Metadata.Venue == "" {
+		item.Metadata.Venue = record.Venue
+		fields = append(fields, "venue")
+	}
+	if record.Year > 0 && item.Metadata.Year == 0 {
+		item.Metadata.Year = record.Year
+		fields = append(fields, "year")
+	}
+	if len(record.Authors) > 0 && len(item.Metadata.Authors) == 0 {
+		item.Metadata.Authors = record.Authors
+		fields = append(fields, "authors")
+	}
+	if record.URL != "" && item.Metadata.URL == "" {
+		item.Metadata.URL = record.URL
+		fields = append(fields, "url")
+	}
+	if record.TotalPages > 0 && (item.Progress == nil || item.Progress.TotalPages == 0) {
+		if err := item.SetTotalPages(record.TotalPages); err == nil {
+			fields = append(fields, "pages")
+		}
+	}
+	for _, tag := range record.Tags {
+		if !item.HasTag(reading.Tag(tag)) {
+			item.AddTag(reading.Tag(tag))
+			fields = appendMissing(fields, "tags")
+		}
+	}
+
+	status := reading.Status(record.Status)
+	if status.IsValid() && status != reading.StatusToRead && item.Status == reading.StatusToRead {
+		if err := item.Restore(status, record.Started, record.Finished); err != nil {
+			return nil, err
+		}
+		switch status {
+		case reading.StatusPaused:
+			item.Metadata.Paused = record.Paused
+		case reading.StatusAbandoned:
+			item.Metadata.Abandoned = record.Abandoned
+			item.Metadata.Reason = record.Reason
+		}
+		fields = append(fields, "status")
+
+		if record.Progress > 0 || record.CurrentPage > 0 {
+			if err := restoreProgress(item, record); err != nil {
+				return nil, err
+			}
+			fields = append(fields, "progress")
+		}
+	}
+
+	if record.Rating > 0 && item.LatestRating() == nil && item.Status == reading.StatusDone {
+		rating, err := reading.NewRating(record.Rating)
+		if err != nil {
+			return nil, err
+		}
+		item.Rating = &rating
+		fields = append(fields, "rating")
+	}
+	if len(record.Reads) > 0 && len(item.History) == 0 {
+		for _, read := range record.Reads {
+			earlier := reading.ReadThrough{Started: read.Started, Finished: read.Finished, Review: read.Review}
+			if read.Rating > 0 {
+				rating, err := reading.NewRating(read.Rating)
+				if err != nil {
+					return nil, err
+				}
+				earlier.Rating = &rating
+			}
+			item.History = append(item.History, earlier)
+		}
+		fields = append(fields, "reads")
+	}
+	if len(record.Sessions) > 0 && len(item.Sessions) == 0 {
+		for _, session := range record.Sessions {
+			item.Sessions = append(item.Sessions, reading.Session{
+				Date:       session.Date,
+				FromPage:   session.FromPage,
+				ToPage:     session.ToPage,
+				Percentage: session.Percentage,
+				Minutes:    session.Minutes,
+			})
+		}
+		fields = append(fields, "sessions")
+	}
+	if record.Review != "" && item.Metadata.Review == "" {
+		item.Metadata.Review = record.Review
+		fields = append(fields, "review")
+	}
+	if record.Notes != "" && item.Metadata.Notes == "" {
+		item.Metadata.Notes = record.Notes
+		fields = append(fields, "notes")
+	}
+
+	return fields, nil
+}
+
+// restoreProgress sets the position and percentage of an item that was
+// being read elsewhere.
+func restoreProgress(item *reading.Item, record ImportRecord) error {
+	progress := &reading.Progress{}
+	if item.Progress != nil {
+		*progress = *item.Progress
+	}
+	if record.CurrentPage > 0 {
+		if err := progress.MoveTo(record.CurrentPage); err != nil {
+			return progressError(item, err)
+		}
+	}
+	if record.Progress > 0 {
+		checked, err := reading.NewProgress(record.Progress)
+		if err != nil {
+			return err
+		}
+		progress.Percentage = checked.Percentage
+	}
+	item.Progress = progress
+	return nil
+}
+
+func appendMissing(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// importHighlight saves a highlight, dropping a page past the end of the
+// item instead of rejecting the passage.
+func importHighlight(item *reading.Item, highlight ImportHighlight) error {
+	added := highlight.Added
+	if added.IsZero() {
+		added = time.Now()
+	}
+
+	_, err := item.AddHighlight(added, highlight.Text, highlight.Page, highlight.Note)
+	var positionErr *reading.PositionError
+	if errors.As(err, &positionErr) {
+		_, err = item.AddHighlight(added, highlight.Text, 0, highlight.Note)
+	}
+	return err
+}
@@ -0,0 +1,306 @@
+package reading
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	querylang "github.com/wguilherme/gitlife/internal/application/reading/query"
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+)
+
+// NextWeights tune how much each factor counts in the recommendation
+// score. A weight of 0 turns a factor off.
+type NextWeights struct {
+	Priority float64 `json:"priority"`
+	Age      float64 `json:"age"`
+	Affinity float64 `json:"affinity"`
+	Length   float64 `json:"length"`
+	WIP      float64 `json:"wip"`
+}
+
+// DefaultNextWeights count every factor the same.
+var DefaultNextWeights = NextWeights{Priority: 1, Age: 1, Affinity: 1, Length: 1, WIP: 1}
+
+// ParseNextWeights overrides weights with a spec such as
+// "priority=2,age=0.5". An empty spec keeps weights as they are.
+func ParseNextWeights(spec string, weights NextWeights) (NextWeights, error) {
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(part, "=")
+		if !found {
+			return weights, fmt.Errorf("invalid weight %q (expected factor=number)", part)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return weights, fmt.Errorf("invalid weight %q: %s is not a number", part, value)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "priority":
+			weights.Priority = weight
+		case "age":
+			weights.Age = weight
+		case "affinity":
+			weights.Affinity = weight
+		case "length":
+			weights.Length = weight
+		case "wip":
+			weights.WIP = weight
+		default:
+			return weights, fmt.Errorf("unknown factor %q (expected priority, age, affinity, length or wip)", name)
+		}
+	}
+	return weights, nil
+}
+
+// NextQuery asks for the to-read items worth reading next. Expression
+// narrows the candidates with the list query language; with Random one
+// candidate is also picked at random, weighted by score.
+type NextQuery struct {
+	Expression string
+	Limit      int
+	Weights    NextWeights
+	Random     bool
+}
+
+// NextResult ranks the candidates, best first. WIP is the number of items
+// being read, which makes long items score lower.
+type NextResult struct {
+	Items   []RecommendationDTO `json:"items"`
+	Total   int                 `json:"total"`
+	WIP     int                 `json:"wip"`
+	Weights NextWeights         `json:"weights"`
+	Picked  *RecommendationDTO  `json:"picked,omitempty"`
+}
+
+// RecommendationDTO is a candidate with its score and what it is made of.
+type RecommendationDTO struct {
+	Item    ItemDTO     `json:"item"`
+	Score   float64     `json:"score"`
+	Reasons []ReasonDTO `json:"reasons"`
+}
+
+// ReasonDTO is the part of the score a factor contributed, already
+// weighted.
+type ReasonDTO struct {
+	Factor string  `json:"factor"`
+	Points float64 `json:"points"`
+	Detail string  `json:"detail"`
+}
+
+// wipLimit is the number of items in progress from which long items get
+// the full WIP penalty.
+const wipLimit = 3
+
+// longItem is the length, per unit, from which an item counts as long.
+var longItem = map[reading.ProgressUnit]int{
+	reading.UnitPages:   500,
+	reading.UnitMinutes: 120,
+	reading.UnitLessons: 40,
+}
+
+// Next ranks the to-read items by a score made of their priority, how long
+// they have been waiting, how well finished items with the same tags were
+// rated, their length and, with several items in progress, a penalty for
+// long items.
+func (s *Service) Next(query NextQuery) (*NextResult, error) {
+	if query.Limit < 0 {
+		return nil, fmt.Errorf("limit cannot be negative")
+	}
+
+	var filter func(*reading.Item) bool
+	if strings.TrimSpace(query.Expression) != "" {
+		predicate, err := querylang.Parse(query.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query: %w", err)
+		}
+		filter = predicate
+	}
+
+	items, err := s.repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load items: %w", err)
+	}
+
+	result := &NextResult{Items: []RecommendationDTO{}, Weights: query.Weights}
+	affinity := newTagAffinity(items)
+	candidates := []*reading.Item{}
+	for _, item := range items {
+		switch {
+		case item.Status == reading.StatusReading:
+			result.WIP++
+		case item.Status == reading.StatusToRead && (filter == nil || filter(item)):
+			candidates = append(candidates, item)
+		}
+	}
+
+	now := time.Now()
+	for _, item := range candidates {
+		result.Items = append(result.Items, recommend(item, query.Weights, affinity, result.WIP, now))
+	}
+	sort.SliceStable(result.Items, func(i, j int) bool {
+		if result.Items[i].Score != result.Items[j].Score {
+			return result.Items[i].Score > result.Items[j].Score
+		}
+		return result.Items[i].Item.Added.Before(result.Items[j].Item.Added)
+	})
+	result.Total = len(result.Items)
+
+	if query.Random && len(result.Items) > 0 {
+		picked := pickWeighted(result.Items)
+		result.Picked = &picked
+	}
+	if query.Limit > 0 && query.Limit < len(result.Items) {
+		result.Items = result.Items[:query.Limit]
+	}
+	return result, nil
+}
+
+func recommend(item *reading.Item, weights NextWeights, affinity tagAffinity, wip int, now time.Time) RecommendationDTO {
+	rec := RecommendationDTO{Item: ToDTO(item), Reasons: []ReasonDTO{}}
+	add := func(factor string, weight, value float64, detail string) {
+		points := math.Round(weight*value*100) / 100
+		if points == 0 {
+			return
+		}
+		rec.Score += points
+		rec.Reasons = append(rec.Reasons, ReasonDTO{Factor: factor, Points: points, Detail: detail})
+	}
+
+	switch item.Priority {
+	case reading.PriorityHigh:
+		add("priority", weights.Priority, 1, "high priority")
+	case reading.PriorityMedium:
+		add("priority", weights.Priority, 0.5, "medium priority")
+	}
+
+	// Items written by hand may have no added date to age from
+	if !item.Metadata.Added.IsZero() {
+		days := now.Sub(item.Metadata.Added).Hours() / 24
+		add("age", weights.Age, math.Min(days/365, 1), "added "+ago(days))
+	}
+
+	if tag, average, count, ok := affinity.best(item); ok {
+		add("affinity", weights.Affinity, (average-3)/2,
+			fmt.Sprintf("you rated items tagged %s %.1f on average (%d reads)", tag, average, count))
+	}
+
+	unit := item.Type.ProgressUnit()
+	length := 0
+	if item.Progress != nil {
+		length = item.Progress.TotalPages
+	}
+	longness := 0.5
+	if length > 0 {
+		longness = math.Min(float64(length)/float64(longItem[unit]), 1)
+		add("length", weights.Length, 1-longness, fmt.Sprintf("%d %s", length, unit))
+	}
+	if wip > 0 {
+		pressure := math.Min(float64(wip)/wipLimit, 1)
+		detail := fmt.Sprintf("%d items in progress", wip)
+		if length == 0 {
+			detail += ", length unknown"
+		}
+		add("wip", weights.WIP, -pressure*longness, detail)
+	}
+
+	rec.Score = math.Round(rec.Score*100) / 100
+	sort.SliceStable(rec.Reasons, func(i, j int) bool {
+		return math.Abs(rec.Reasons[i].Points) > math.Abs(rec.Reasons[j].Points)
+	})
+	return rec
+}
+
+// tagAffinity holds the ratings of every finished read per tag, counting
+// nested tags for their parents too.
+type tagAffinity map[reading.Tag][]int
+
+func newTagAffinity(items []*reading.Item) tagAffinity {
+	affinity := tagAffinity{}
+	for _, item := range items {
+		for _, read := range item.ReadThroughs() {
+			if read.Rating == nil || read.Rating.Value() == 0 {
+				continue
+			}
+			for tag := range withParents(item.Tags) {
+				affinity[tag] = append(affinity[tag], read.Rating.Value())
+			}
+		}
+	}
+	return affinity
+}
+
+// best returns the tag of the item whose finished items were rated
+// highest, preferring the most nested tag on a tie.
+func (a tagAffinity) best(item *reading.Item) (reading.Tag, float64, int, bool) {
+	var best reading.Tag
+	average, count, found := 0.0, 0, false
+	for tag := range withParents(item.Tags) {
+		ratings := a[tag]
+		if len(ratings) == 0 {
+			continue
+		}
+		sum := 0
+		for _, rating := range ratings {
+			sum += rating
+		}
+		avg := float64(sum) / float64(len(ratings))
+		if !found || avg > average || (avg == average && len(tag) > len(best)) {
+			best, average, count, found = tag, avg, len(ratings), true
+		}
+	}
+	return best, average, count, found
+}
+
+func withParents(tags []reading.Tag) map[reading.Tag]bool {
+	all := make(map[reading.Tag]bool)
+	for _, tag := range tags {
+		for ; tag != ""; tag = tag.Parent() {
+			all[tag] = true
+		}
+	}
+	return all
+}
+
+// pickWeighted picks a candidate at random; the higher the score, the more
+// likely. The lowest score still gets a small chance.
+func pickWeighted(items []RecommendationDTO) RecommendationDTO {
+	lowest := items[len(items)-1].Score
+	total := 0.0
+	for _, item := range items {
+		total += item.Score - lowest + 0.1
+	}
+
+	target := rand.Float64() * total
+	for _, item := range items {
+		target -= item.Score - lowest + 0.1
+		if target < 0 {
+			return item
+		}
+	}
+	return items[len(items)-1]
+}
+
+func ago(days float64) string {
+	switch {
+	case days < 1:
+		return "today"
+	case days < 2:
+		return "yesterday"
+	case days < 60:
+		return fmt.Sprintf("%d days ago", int(days))
+	case days < 365:
+		return fmt.Sprintf("%d months ago", int(days/30))
+	default:
+		return fmt.Sprintf("%.1f years ago", days/365)
+	}
+}
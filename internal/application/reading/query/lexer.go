@@ -0,0 +1,157 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenTerm
+)
+
+type token struct {
+	kind  tokenKind
+	pos   int
+	field string
+	op    string
+	value string
+}
+
+// SyntaxError reports an invalid query and where it went wrong.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos+1, e.Msg)
+}
+
+var operators = []string{">=", "<=", "!=", ":", "=", ">", "<"}
+
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := []token{}
+	pos := 0
+
+	for pos < len(runes) {
+		r := runes[pos]
+		switch {
+		case unicode.IsSpace(r):
+			pos++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, pos: pos})
+			pos++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, pos: pos})
+			pos++
+		case r == '-' && pos+1 < len(runes) && !unicode.IsSpace(runes[pos+1]):
+			tokens = append(tokens, token{kind: tokenNot, pos: pos})
+			pos++
+		default:
+			tok, next, err := lexTerm(runes, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			pos = next
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+// lexTerm reads "field<op>value", a quoted phrase or a bare word. Bare AND,
+// OR and NOT are keywords.
+func lexTerm(runes []rune, start int) (token, int, error) {
+	pos := start
+
+	if runes[pos] == '"' {
+		value, next, err := lexQuoted(runes, pos)
+		if err != nil {
+			return token{}, 0, err
+		}
+		return token{kind: tokenTerm, pos: start, value: value}, next, nil
+	}
+
+	for pos < len(runes) && (unicode.IsLetter(runes[pos]) || unicode.IsDigit(runes[pos]) || runes[pos] == '_') {
+		pos++
+	}
+	field := string(runes[start:pos])
+
+	if field != "" {
+		rest := string(runes[pos:])
+		for _, op := range operators {
+			if !strings.HasPrefix(rest, op) {
+				continue
+			}
+
+			valueStart := pos + len([]rune(op))
+			if valueStart < len(runes) && runes[valueStart] == '"' {
+				value, next, err := lexQuoted(runes, valueStart)
+				if err != nil {
+					return token{}, 0, err
+				}
+				return token{kind: tokenTerm, pos: start, field: strings.ToLower(field), op: op, value: value}, next, nil
+			}
+
+			end := wordEnd(runes, valueStart)
+			if end == valueStart {
+				return token{}, 0, &SyntaxError{Pos: valueStart, Msg: fmt.Sprintf("missing value after %s%s", field, op)}
+			}
+			value := string(runes[valueStart:end])
+			return token{kind: tokenTerm, pos: start, field: strings.ToLower(field), op: op, value: value}, end, nil
+		}
+	}
+
+	end := wordEnd(runes, start)
+	if end == start {
+		return token{}, 0, &SyntaxError{Pos: start, Msg: fmt.Sprintf("unexpected %q", string(runes[start]))}
+	}
+	word := string(runes[start:end])
+
+	switch word {
+	case "AND":
+		return token{kind: tokenAnd, pos: start}, end, nil
+	case "OR":
+		return token{kind: tokenOr, pos: start}, end, nil
+	case "NOT":
+		return token{kind: tokenNot, pos: start}, end, nil
+	}
+	return token{kind: tokenTerm, pos: start, value: word}, end, nil
+}
+
+func lexQuoted(runes []rune, start int) (string, int, error) {
+	var b strings.Builder
+	for pos := start + 1; pos < len(runes); pos++ {
+		switch runes[pos] {
+		case '\\':
+			if pos+1 < len(runes) {
+				pos++
+				b.WriteRune(runes[pos])
+			}
+		case '"':
+			return b.String(), pos + 1, nil
+		default:
+			b.WriteRune(runes[pos])
+		}
+	}
+	return "", 0, &SyntaxError{Pos: start, Msg: "unterminated quoted string"}
+}
+
+func wordEnd(runes []rune, start int) int {
+	pos := start
+	for pos < len(runes) && !unicode.IsSpace(runes[pos]) && runes[pos] != '(' && runes[pos] != ')' && runes[pos] != '"' {
+		pos++
+	}
+	return pos
+}
@@ -0,0 +1,159 @@
+// Package query implements the search language of the reading list, e.g.
+//
+//	type:book rating>=4 tag:go -tag:frontend finished:2025 author:"Martin"
+//
+// Terms are joined with AND by default; OR, NOT (or a leading "-") and
+// parentheses combine them. A term without a field searches the text of
+// the item.
+package query
+
+import (
+	"fmt"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+)
+
+// Predicate reports whether an item matches a compiled query.
+type Predicate func(item *reading.Item) bool
+
+// Parse compiles a query into a predicate. An empty query matches
+// everything.
+func Parse(input string) (Predicate, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return func(*reading.Item) bool { return true }, nil
+	}
+
+	predicate, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		if tok.kind == tokenRParen {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "unbalanced \")\""}
+		}
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "unexpected input"}
+	}
+
+	return predicate, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// parseOr handles: and ("OR" and)*
+func (p *parser) parseOr() (Predicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = or(left, right)
+	}
+
+	return left, nil
+}
+
+// parseAnd handles: unary (["AND"] unary)*
+func (p *parser) parseAnd() (Predicate, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenTerm, tokenNot, tokenLParen:
+		default:
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = and(left, right)
+	}
+}
+
+// parseUnary handles: ("NOT" | "-") unary | "(" or ")" | term
+func (p *parser) parseUnary() (Predicate, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenNot:
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return not(operand), nil
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "missing \")\""}
+		}
+		return inner, nil
+	case tokenTerm:
+		return compileTerm(tok)
+	case tokenEOF:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "unexpected end of query"}
+	default:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %s", describe(tok))}
+	}
+}
+
+func describe(tok token) string {
+	switch tok.kind {
+	case tokenRParen:
+		return "\")\""
+	case tokenAnd:
+		return "AND"
+	case tokenOr:
+		return "OR"
+	default:
+		return "token"
+	}
+}
+
+func and(left, right Predicate) Predicate {
+	return func(item *reading.Item) bool { return left(item) && right(item) }
+}
+
+func or(left, right Predicate) Predicate {
+	return func(item *reading.Item) bool { return left(item) || right(item) }
+}
+
+func not(operand Predicate) Predicate {
+	return func(item *reading.Item) bool { return !operand(item) }
+}
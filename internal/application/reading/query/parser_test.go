@@ -0,0 +1,149 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+)
+
+func library() []*reading.Item {
+	finished := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+	rating := reading.Rating(5)
+
+	return []*reading.Item{
+		{
+			Title:    "Clean Code",
+			Author:   "Robert Martin",
+			Type:     reading.TypeBook,
+			Status:   reading.StatusDone,
+			Tags:     []reading.Tag{"programming"},
+			Rating:   &rating,
+			Metadata: reading.Metadata{Finished: &finished},
+		},
+		{
+			Title:  "Dune",
+			Author: "Frank Herbert",
+			Type:   reading.TypeBook,
+			Status: reading.StatusReading,
+			Tags:   []reading.Tag{"scifi"},
+		},
+		{
+			Title:  "Go Talk",
+			Author: "Rob Pike",
+			Type:   reading.TypeVideo,
+			Status: reading.StatusToRead,
+			Tags:   []reading.Tag{"programming/go", "frontend"},
+		},
+		{
+			Title:  "The Go Programming Language",
+			Author: "Alan Donovan",
+			Type:   reading.TypeBook,
+			Status: reading.StatusToRead,
+			Tags:   []reading.Tag{"programming/go"},
+		},
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Clean Code", "Dune", "Go Talk", "The Go Programming Language"}},
+		{"type:book", []string{"Clean Code", "Dune", "The Go Programming Language"}},
+		{"TYPE:Book", []string{"Clean Code", "Dune", "The Go Programming Language"}},
+		{"dune", []string{"Dune"}},
+		{`"go programming"`, []string{"The Go Programming Language"}},
+		{`author:"robert martin"`, []string{"Clean Code"}},
+		{`title="go talk"`, []string{"Go Talk"}},
+		{`title:"\"go"`, []string{}},
+		{"tag:programming", []string{"Clean Code", "Go Talk", "The Go Programming Language"}},
+		{"tag=programming", []string{"Clean Code"}},
+		{"tag:programming/*", []string{"Go Talk", "The Go Programming Language"}},
+		{"tag:programming -tag:frontend", []string{"Clean Code", "The Go Programming Language"}},
+		{"tag:programming NOT tag:frontend", []string{"Clean Code", "The Go Programming Language"}},
+		{"NOT NOT type:video", []string{"Go Talk"}},
+		{"-(tag:scifi OR tag:frontend)", []string{"Clean Code", "The Go Programming Language"}},
+		{"rating>=4", []string{"Clean Code"}},
+		{"rating<4", []string{}},
+		{"finished:2025-03", []string{"Clean Code"}},
+
+		// AND binds tighter than OR, with or without the keyword
+		{"type:video OR status:done type:book", []string{"Clean Code", "Go Talk"}},
+		{"type:video OR status:done AND type:book", []string{"Clean Code", "Go Talk"}},
+		{"(type:video OR status:done) type:book", []string{"Clean Code"}},
+		{"status:to-read type:book OR type:video", []string{"Go Talk", "The Go Programming Language"}},
+		{"status:to-read (type:book OR tag:scifi)", []string{"The Go Programming Language"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			predicate, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.query, err)
+			}
+
+			got := []string{}
+			for _, item := range library() {
+				if predicate(item) {
+					got = append(got, string(item.Title))
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) matches %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{"(type:book", 0, `missing ")"`},
+		{"type:book)", 9, `unbalanced ")"`},
+		{"()", 1, `unexpected ")"`},
+		{"AND type:book", 0, "unexpected AND"},
+		{"type:book OR", 12, "unexpected end of query"},
+		{"NOT", 3, "unexpected end of query"},
+		{"tag:go NOT", 10, "unexpected end of query"},
+		{`title:"dune`, 6, "unterminated quoted string"},
+		{`"dune`, 0, "unterminated quoted string"},
+		{"type:", 5, "missing value after type:"},
+		{"type:magazine", 0, `invalid type "magazine"`},
+		{"type>book", 0, "operator > is not supported for type"},
+		{"dune rating>=abc", 5, `rating expects a number, got "abc"`},
+		{"finished:March", 0, "finished expects a date"},
+		{"tag:[", 0, `invalid tag pattern "["`},
+		{"foo:bar", 0, `unknown field "foo"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			predicate, err := Parse(tt.query)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded, want an error", tt.query)
+			}
+			if predicate != nil {
+				t.Errorf("Parse(%q) returned a predicate with the error", tt.query)
+			}
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error %v is not a *SyntaxError", tt.query, err)
+			}
+			if syntaxErr.Pos != tt.pos {
+				t.Errorf("Parse(%q) error at %d, want %d", tt.query, syntaxErr.Pos, tt.pos)
+			}
+			if !strings.Contains(syntaxErr.Msg, tt.msg) {
+				t.Errorf("Parse(%q) error %q, want it to contain %q", tt.query, syntaxErr.Msg, tt.msg)
+			}
+		})
+	}
+}
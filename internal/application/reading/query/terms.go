@@ -0,0 +1,319 @@
+package query
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+)
+
+func compileTerm(tok token) (Predicate, error) {
+	fail := func(format string, args ...interface{}) (Predicate, error) {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	switch tok.field {
+	case "":
+		text := strings.ToLower(tok.value)
+		return func(item *reading.Item) bool { return item.ContainsText(text) }, nil
+
+	case "title", "author", "url", "isbn", "doi", "venue", "notes", "review", "reason":
+		field := tok.field
+		return compileText(tok, func(item *reading.Item) []string {
+			return []string{textField(item, field)}
+		})
+
+	case "highlight", "highlights":
+		return compileText(tok, func(item *reading.Item) []string {
+			values := []string{}
+			for _, highlight := range item.Highlights {
+				values = append(values, highlight.Text, highlight.Note)
+			}
+			return values
+		})
+
+	case "tag", "tags":
+		return compileTag(tok)
+
+	case "type", "status", "priority":
+		value := strings.ToLower(tok.value)
+		get := enumField(tok.field)
+		if !isValidEnum(tok.field, value) {
+			return fail("invalid %s %q", tok.field, tok.value)
+		}
+
+		switch tok.op {
+		case ":", "=":
+			return func(item *reading.Item) bool { return get(item) == value }, nil
+		case "!=":
+			return func(item *reading.Item) bool { return get(item) != value }, nil
+		}
+		return fail("operator %s is not supported for %s", tok.op, tok.field)
+
+	case "rating", "progress", "pages", "page", "reads", "year":
+		value, err := strconv.Atoi(strings.TrimSuffix(tok.value, "%"))
+		if err != nil {
+			return fail("%s expects a number, got %q", tok.field, tok.value)
+		}
+		return compileNumber(tok, value, numberField(tok.field))
+
+	case "added", "started", "finished", "paused", "abandoned":
+		from, to, err := ParsePeriod(tok.value)
+		if err != nil {
+			return fail("%s expects a date (2025, 2025-03 or 2025-03-14), got %q", tok.field, tok.value)
+		}
+		return compileDate(tok, from, to, dateField(tok.field))
+	}
+
+	return fail("unknown field %q", tok.field)
+}
+
+func compileText(tok token, values func(*reading.Item) []string) (Predicate, error) {
+	value := strings.ToLower(tok.value)
+
+	var match func(string) bool
+	switch tok.op {
+	case ":":
+		match = func(s string) bool { return strings.Contains(strings.ToLower(s), value) }
+	case "=":
+		match = func(s string) bool { return strings.ToLower(s) == value }
+	case "!=":
+		return func(item *reading.Item) bool {
+			for _, s := range values(item) {
+				if strings.ToLower(s) == value {
+					return false
+				}
+			}
+			return true
+		}, nil
+	default:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("operator %s is not supported for %s", tok.op, tok.field)}
+	}
+
+	return func(item *reading.Item) bool {
+		for _, s := range values(item) {
+			if match(s) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// compileTag matches tags with shell-style globs, so "tag:programming/*"
+// matches every nested tag and "tag:go*" matches go and golang. With ":"
+// a tag also matches the tags nested under it, so "tag:programming" finds
+// programming/go; "=" only matches the tag itself.
+func compileTag(tok token) (Predicate, error) {
+	pattern := strings.ToLower(strings.TrimPrefix(tok.value, "#"))
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("invalid tag pattern %q", tok.value)}
+	}
+
+	matches := func(item *reading.Item) bool {
+		for _, tag := range item.Tags {
+			tag = reading.Tag(strings.ToLower(string(tag)))
+			for ; tag != ""; tag = tag.Parent() {
+				if ok, _ := path.Match(pattern, string(tag)); ok {
+					return true
+				}
+				if tok.op == "=" || tok.op == "!=" {
+					break
+				}
+			}
+		}
+		return false
+	}
+
+	switch tok.op {
+	case ":", "=":
+		return matches, nil
+	case "!=":
+		return not(matches), nil
+	}
+	return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("operator %s is not supported for tag", tok.op)}
+}
+
+// compileNumber compares a numeric field. Items without a value (no rating,
+// no progress) never match.
+func compileNumber(tok token, value int, get func(*reading.Item) (int, bool)) (Predicate, error) {
+	var compare func(int) bool
+	switch tok.op {
+	case ":", "=":
+		compare = func(v int) bool { return v == value }
+	case "!=":
+		compare = func(v int) bool { return v != value }
+	case ">":
+		compare = func(v int) bool { return v > value }
+	case ">=":
+		compare = func(v int) bool { return v >= value }
+	case "<":
+		compare = func(v int) bool { return v < value }
+	case "<=":
+		compare = func(v int) bool { return v <= value }
+	}
+
+	return func(item *reading.Item) bool {
+		v, ok := get(item)
+		return ok && compare(v)
+	}, nil
+}
+
+// compileDate compares a date field with the period [from, to): ":" means
+// within the period, ">" after it and "<" before it.
+func compileDate(tok token, from, to time.Time, get func(*reading.Item) *time.Time) (Predicate, error) {
+	var compare func(time.Time) bool
+	switch tok.op {
+	case ":", "=":
+		compare = func(t time.Time) bool { return !t.Before(from) && t.Before(to) }
+	case "!=":
+		compare = func(t time.Time) bool { return t.Before(from) || !t.Before(to) }
+	case ">":
+		compare = func(t time.Time) bool { return !t.Before(to) }
+	case ">=":
+		compare = func(t time.Time) bool { return !t.Before(from) }
+	case "<":
+		compare = func(t time.Time) bool { return t.Before(from) }
+	case "<=":
+		compare = func(t time.Time) bool { return t.Before(to) }
+	}
+
+	return func(item *reading.Item) bool {
+		t := get(item)
+		return t != nil && compare(*t)
+	}, nil
+}
+
+// ParsePeriod turns 2025, 2025-03 or 2025-03-14 into the half-open range
+// of days it covers.
+func ParsePeriod(value string) (time.Time, time.Time, error) {
+	layouts := []struct {
+		layout string
+		years  int
+		months int
+		days   int
+	}{
+		{"2006", 1, 0, 0},
+		{"2006-01", 0, 1, 0},
+		{"2006-01-02", 0, 0, 1},
+	}
+
+	for _, l := range layouts {
+		if from, err := time.Parse(l.layout, value); err == nil {
+			return from, from.AddDate(l.years, l.months, l.days), nil
+		}
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+func isValidEnum(field, value string) bool {
+	switch field {
+	case "type":
+		return reading.ItemType(value).IsValid()
+	case "status":
+		return reading.Status(value).IsValid()
+	default:
+		return reading.Priority(value).IsValid()
+	}
+}
+
+func enumField(field string) func(*reading.Item) string {
+	return func(item *reading.Item) string {
+		switch field {
+		case "type":
+			return string(item.Type)
+		case "status":
+			return string(item.Status)
+		default:
+			return string(item.Priority)
+		}
+	}
+}
+
+func numberField(field string) func(*reading.Item) (int, bool) {
+	return func(item *reading.Item) (int, bool) {
+		switch field {
+		case "rating":
+			rating := item.LatestRating()
+			if rating == nil {
+				return 0, false
+			}
+			return rating.Value(), true
+		case "reads":
+			return len(item.ReadThroughs()), true
+		case "year":
+			return item.Metadata.Year, item.Metadata.Year > 0
+		case "progress":
+			switch {
+			case item.Progress != nil:
+				return item.Progress.Percentage, true
+			case item.Status == reading.StatusDone:
+				return 100, true
+			default:
+				return 0, true
+			}
+		case "pages":
+			if item.Type.ProgressUnit() != reading.UnitPages {
+				return 0, false
+			}
+			if item.Progress == nil || item.Progress.TotalPages == 0 {
+				return 0, false
+			}
+			return item.Progress.TotalPages, true
+		default:
+			if item.Type.ProgressUnit() != reading.UnitPages {
+				return 0, false
+			}
+			if item.Progress == nil || item.Progress.CurrentPage == 0 {
+				return 0, false
+			}
+			return item.Progress.CurrentPage, true
+		}
+	}
+}
+
+func dateField(field string) func(*reading.Item) *time.Time {
+	return func(item *reading.Item) *time.Time {
+		switch field {
+		case "added":
+			if item.Metadata.Added.IsZero() {
+				return nil
+			}
+			return &item.Metadata.Added
+		case "started":
+			return item.Metadata.Started
+		case "paused":
+			return item.Metadata.Paused
+		case "abandoned":
+			return item.Metadata.Abandoned
+		default:
+			return item.Metadata.Finished
+		}
+	}
+}
+
+func textField(item *reading.Item, field string) string {
+	switch field {
+	case "title":
+		return string(item.Title)
+	case "author":
+		return string(item.Author)
+	case "url":
+		return item.Metadata.URL
+	case "notes":
+		return item.Metadata.Notes
+	case "reason":
+		return item.Metadata.Reason
+	case "isbn":
+		return item.Metadata.ISBN
+	case "doi":
+		return item.Metadata.DOI
+	case "venue":
+		return item.Metadata.Venue
+	default:
+		return item.Metadata.Review
+	}
+}
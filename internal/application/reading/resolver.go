@@ -0,0 +1,151 @@
+package reading
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// AmbiguousMatchError is returned when a reference matches more than one item.
+type AmbiguousMatchError struct {
+	Query      string
+	Candidates []ItemDTO
+}
+
+func (e *AmbiguousMatchError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ambiguous match for %q, candidates:", e.Query)
+	for _, candidate := range e.Candidates {
+		fmt.Fprintf(&b, "\n  %s  %s (%s)", candidate.ID, candidate.Title, candidate.Author)
+	}
+	return b.String()
+}
+
+// NotFoundError is returned when a reference matches no item.
+type NotFoundError struct {
+	Query string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no item matches %q", e.Query)
+}
+
+// StorageError is returned when the reading list could not be loaded or
+// saved, as opposed to a request the service refused.
+type StorageError struct {
+	Err error
+}
+
+func (e *StorageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StorageError) Unwrap() error {
+	return e.Err
+}
+
+// ResolveID turns a user supplied reference into an item ID. The reference
+// may be a full ID, a unique ID prefix or (part of) the title and author.
+func (s *Service) ResolveID(query string) (string, error) {
+	item, err := s.findItem(query)
+	if err != nil {
+		return "", err
+	}
+	return string(item.ID), nil
+}
+
+func (s *Service) findItem(query string) (*reading.Item, error) {
+	if _, err := reading.NewItemID(query); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.FindAll()
+	if err != nil {
+		return nil, &StorageError{Err: fmt.Errorf("failed to load items: %w", err)}
+	}
+
+	return resolveItem(items, query)
+}
+
+func resolveItem(items []*reading.Item, query string) (*reading.Item, error) {
+	for _, item := range items {
+		if string(item.ID) == query {
+			return item, nil
+		}
+	}
+
+	lowered := strings.ToLower(query)
+	matches := filterItems(items, func(item *reading.Item) bool {
+		return strings.HasPrefix(strings.ToLower(string(item.ID)), lowered)
+	})
+	if len(matches) == 0 {
+		matches = matchByTitle(items, query)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, &NotFoundError{Query: query}
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, &AmbiguousMatchError{Query: query, Candidates: ToDTOList(matches)}
+	}
+}
+
+// matchByTitle prefers items whose title equals the query and falls back to
+// items whose title and author contain every word of the query. Case and
+// accents are ignored, so "programacao" finds "Programação em Go" and the old
+// "Clean-Code-Robert-Martin" style IDs still resolve.
+func matchByTitle(items []*reading.Item, query string) []*reading.Item {
+	words := strings.Fields(normalize(query))
+	if len(words) == 0 {
+		return nil
+	}
+
+	exact := filterItems(items, func(item *reading.Item) bool {
+		return normalize(string(item.Title)) == strings.Join(words, " ")
+	})
+	if len(exact) > 0 {
+		return exact
+	}
+
+	return filterItems(items, func(item *reading.Item) bool {
+		haystack := " " + normalize(string(item.Title)+" "+string(item.Author)) + " "
+		for _, word := range words {
+			if !strings.Contains(haystack, word) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func filterItems(items []*reading.Item, keep func(*reading.Item) bool) []*reading.Item {
+	filtered := []*reading.Item{}
+	for _, item := range items {
+		if keep(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+var foldAccents = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// normalize lowercases s, strips accents and turns punctuation into spaces.
+func normalize(s string) string {
+	folded, _, err := transform.String(foldAccents, s)
+	if err != nil {
+		folded = s
+	}
+
+	fields := strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
@@ -1,9 +1,12 @@
 package reading
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	querylang "github.com/wguilherme/gitlife/internal/application/reading/query"
 	"github.com/wguilherme/gitlife/internal/domain/reading"
 )
 
@@ -17,6 +20,19 @@ func NewService(repo reading.Repository) *Service {
 	}
 }
 
+// describe returns the repository to write an operation on items through,
+// so the repositories that record their changes can tell what happened.
+func (s *Service) describe(operation string, items ...*reading.Item) reading.Repository {
+	return s.record(reading.Change{Operation: operation, Items: items})
+}
+
+func (s *Service) record(change reading.Change) reading.Repository {
+	if recorder, ok := s.repo.(reading.ChangeRecorder); ok {
+		return recorder.WithChange(change)
+	}
+	return s.repo
+}
+
 func (s *Service) ListAll() ([]ItemDTO, error) {
 	items, err := s.repo.FindAll()
 	if err != nil {
@@ -46,13 +62,120 @@ func (s *Service) ListByTag(tag string) ([]ItemDTO, error) {
 	return ToDTOList(items), nil
 }
 
-func (s *Service) GetItem(id string) (*ItemDTO, error) {
-	itemID, err := reading.NewItemID(id)
+func (s *Service) Query(query ListQuery) (*ListResult, error) {
+	items, total, err := s.find(query)
 	if err != nil {
 		return nil, err
 	}
 
-	item, err := s.repo.FindByID(itemID)
+	return &ListResult{
+		Items: ToDTOList(items),
+		Count: len(items),
+		Total: total,
+	}, nil
+}
+
+// Export returns the items matching query in full, with their earlier
+// reads, highlights and sessions, for the exports that can be imported back.
+func (s *Service) Export(query ListQuery) ([]ExportItemDTO, error) {
+	items, _, err := s.find(query)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := []ExportItemDTO{}
+	for _, item := range items {
+		dtos = append(dtos, ToExportDTO(item))
+	}
+	return dtos, nil
+}
+
+// find returns the page of items matching query and how many match in all.
+func (s *Service) find(query ListQuery) ([]*reading.Item, int, error) {
+	options, err := queryOptions(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if repo, ok := s.repo.(reading.QueryRepository); ok {
+		items, total, err := repo.Find(options)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to query items: %w", err)
+		}
+		return items, total, nil
+	}
+
+	all, err := s.repo.FindAll()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query items: %w", err)
+	}
+	items, total := reading.Query(all, options)
+	return items, total, nil
+}
+
+func queryOptions(query ListQuery) (reading.QueryOptions, error) {
+	options := reading.QueryOptions{
+		Search: query.Search,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+
+	if query.Limit < 0 || query.Offset < 0 {
+		return options, fmt.Errorf("limit and offset cannot be negative")
+	}
+
+	if strings.TrimSpace(query.Expression) != "" {
+		predicate, err := querylang.Parse(query.Expression)
+		if err != nil {
+			return options, fmt.Errorf("invalid query: %w", err)
+		}
+		options.Filter = predicate
+	}
+
+	if query.Status != "" {
+		status := reading.Status(query.Status)
+		if !status.IsValid() {
+			return options, fmt.Errorf("invalid status: %s", query.Status)
+		}
+		options.Status = &status
+	}
+
+	if query.Priority != "" {
+		priority := reading.Priority(query.Priority)
+		if !priority.IsValid() {
+			return options, fmt.Errorf("invalid priority: %s", query.Priority)
+		}
+		options.Priority = &priority
+	}
+
+	if query.Type != "" {
+		itemType := reading.ItemType(query.Type)
+		if !itemType.IsValid() {
+			return options, fmt.Errorf("invalid type: %s", query.Type)
+		}
+		options.Type = &itemType
+	}
+
+	for _, tag := range query.Tags {
+		if tag != "" {
+			options.Tags = append(options.Tags, reading.Tag(tag))
+		}
+	}
+
+	if query.Sort != "" {
+		key := reading.SortKey(strings.TrimPrefix(query.Sort, "-"))
+		if !key.IsValid() {
+			return options, fmt.Errorf("invalid sort key: %s", query.Sort)
+		}
+		options.SortBy = key
+		options.Descending = strings.HasPrefix(query.Sort, "-")
+	}
+
+	return options, nil
+}
+
+func (s *Service) GetItem(id string) (*ItemDTO, error) {
+	item, err := s.findItem(id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get item: %w", err)
 	}
@@ -61,10 +184,10 @@ func (s *Service) GetItem(id string) (*ItemDTO, error) {
 	return &dto, nil
 }
 
-func (s *Service) AddItem(cmd AddItemCommand) error {
+func (s *Service) AddItem(cmd AddItemCommand) (*ItemDTO, error) {
 	title, err := reading.NewTitle(cmd.Title)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	author := reading.Author(cmd.Author)
@@ -79,7 +202,7 @@ func (s *Service) AddItem(cmd AddItemCommand) error {
 
 	item, err := reading.NewItem(title, author, itemType)
 	if err != nil {
-		return fmt.Errorf("failed to create item: %w", err)
+		return nil, fmt.Errorf("failed to create item: %w", err)
 	}
 
 	if cmd.Priority != "" {
@@ -89,94 +212,277 @@ func (s *Service) AddItem(cmd AddItemCommand) error {
 		}
 	}
 
-	for _, tag := range cmd.Tags {
-		item.AddTag(reading.Tag(tag))
+	tags, err := parseTags(cmd.Tags)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		item.AddTag(tag)
 	}
 
 	if cmd.URL != "" {
 		item.Metadata.URL = cmd.URL
 	}
 
-	return s.repo.Save(item)
+	if cmd.TotalPages != 0 {
+		if err := item.SetTotalPages(cmd.TotalPages); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.describe("add", item).Save(item); err != nil {
+		return nil, err
+	}
+
+	dto := ToDTO(item)
+	return &dto, nil
 }
 
-func (s *Service) StartReading(id string) error {
-	itemID, err := reading.NewItemID(id)
+func (s *Service) UpdateItem(cmd UpdateItemCommand) (*ItemDTO, error) {
+	item, err := s.findItem(cmd.ItemID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if cmd.Title != nil || cmd.Author != nil {
+		title := item.Title
+		if cmd.Title != nil {
+			title, err = reading.NewTitle(*cmd.Title)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		author := item.Author
+		if cmd.Author != nil {
+			author = reading.Author(*cmd.Author)
+			if author == "" {
+				author = "Unknown"
+			}
+		}
+
+		item.Rename(title, author)
+	}
+
+	if cmd.Type != nil {
+		if err := item.SetType(reading.ItemType(*cmd.Type)); err != nil {
+			return nil, err
+		}
+	}
+
+	if cmd.Priority != nil {
+		if err := item.SetPriority(reading.Priority(*cmd.Priority)); err != nil {
+			return nil, err
+		}
+	}
+
+	if cmd.Tags != nil {
+		tags, err := parseTags(*cmd.Tags)
+		if err != nil {
+			return nil, err
+		}
+		item.Tags = []reading.Tag{}
+		for _, tag := range tags {
+			item.AddTag(tag)
+		}
+	}
+
+	if cmd.URL != nil {
+		item.Metadata.URL = *cmd.URL
 	}
 
-	item, err := s.repo.FindByID(itemID)
+	if cmd.Notes != nil {
+		item.Metadata.Notes = *cmd.Notes
+	}
+
+	if cmd.TotalPages != nil {
+		if err := item.SetTotalPages(*cmd.TotalPages); err != nil {
+			return nil, progressError(item, err)
+		}
+	}
+
+	if err := s.describe("edit", item).Update(item); err != nil {
+		return nil, &StorageError{Err: err}
+	}
+
+	dto := ToDTO(item)
+	return &dto, nil
+}
+
+func (s *Service) StartReading(id string) error {
+	item, err := s.findItem(id)
 	if err != nil {
-		return fmt.Errorf("item not found: %w", err)
+		return err
 	}
 
 	if err := item.Start(time.Now()); err != nil {
 		return err
 	}
 
-	return s.repo.Update(item)
+	return s.describe("start", item).Update(item)
 }
 
-func (s *Service) UpdateProgress(cmd UpdateProgressCommand) error {
-	itemID, err := reading.NewItemID(cmd.ItemID)
+func (s *Service) UpdateProgress(cmd UpdateProgressCommand) (*ItemDTO, error) {
+	item, err := s.findItem(cmd.ItemID)
 	if err != nil {
+		return nil, err
+	}
+
+	unit := item.Type.ProgressUnit()
+	if cmd.Unit != "" && reading.ProgressUnit(cmd.Unit) != unit {
+		return nil, fmt.Errorf("progress of a %s is tracked in %s", item.Type, unit)
+	}
+
+	progress := &reading.Progress{}
+	if item.Progress != nil {
+		*progress = *item.Progress
+	}
+
+	if cmd.CurrentPage != 0 {
+		if err := progress.MoveTo(cmd.CurrentPage); err != nil {
+			return nil, progressError(item, err)
+		}
+	}
+
+	switch {
+	case cmd.Percentage != nil && cmd.CurrentPage != 0 && progress.TotalPages > 0:
+		// The page already set the percentage; a different one contradicts it
+		if *cmd.Percentage != progress.Percentage {
+			return nil, fmt.Errorf("%d%% does not match %s %d of %d (%d%%)",
+				*cmd.Percentage, unit.Singular(), cmd.CurrentPage, progress.TotalPages, progress.Percentage)
+		}
+	case cmd.Percentage != nil:
+		if err := progress.SetPercentage(*cmd.Percentage); err != nil {
+			return nil, err
+		}
+	case cmd.CurrentPage == 0:
+		return nil, fmt.Errorf("percentage or current %s is required", unit.Singular())
+	case progress.TotalPages == 0:
+		return nil, fmt.Errorf("percentage is required until the total %s of %q is set", unit, item.Title)
+	}
+
+	if err := item.LogSession(time.Now(), progress, cmd.Minutes); err != nil {
+		return nil, err
+	}
+
+	if err := s.describe("progress", item).Update(item); err != nil {
+		return nil, err
+	}
+
+	dto := ToDTO(item)
+	return &dto, nil
+}
+
+// progressError names the unit of the item in position errors, e.g.
+// "page 420 is past the end of "Dune" (412 pages)".
+func progressError(item *reading.Item, err error) error {
+	var positionErr *reading.PositionError
+	if !errors.As(err, &positionErr) {
 		return err
 	}
 
-	item, err := s.repo.FindByID(itemID)
+	unit := item.Type.ProgressUnit()
+	return fmt.Errorf("%s %d is past the end of %q (%d %s)",
+		unit.Singular(), positionErr.Position, item.Title, positionErr.Total, unit)
+}
+
+// ReadThroughs returns every completed read of an item, oldest first.
+func (s *Service) ReadThroughs(id string) ([]ReadThroughDTO, error) {
+	item, err := s.findItem(id)
+	if err != nil {
+		return nil, err
+	}
+	return ToReadThroughDTOList(item.ReadThroughs()), nil
+}
+
+// Sessions returns the reading sessions of an item, oldest first.
+func (s *Service) Sessions(id string) ([]SessionDTO, error) {
+	item, err := s.findItem(id)
 	if err != nil {
-		return fmt.Errorf("item not found: %w", err)
+		return nil, err
 	}
+	return ToSessionDTOList(item), nil
+}
 
-	progress, err := reading.NewProgress(cmd.Percentage)
+func (s *Service) FinishReading(cmd FinishItemCommand) error {
+	item, err := s.findItem(cmd.ItemID)
 	if err != nil {
 		return err
 	}
 
-	if cmd.CurrentPage > 0 {
-		progress.CurrentPage = cmd.CurrentPage
+	rating, err := reading.NewRating(cmd.Rating)
+	if err != nil {
+		return err
 	}
 
-	if err := item.UpdateProgress(progress); err != nil {
+	if err := item.Finish(time.Now(), rating); err != nil {
 		return err
 	}
 
-	return s.repo.Update(item)
+	if cmd.Review != "" {
+		item.Metadata.Review = cmd.Review
+	}
+
+	return s.describe("finish", item).Update(item)
 }
 
-func (s *Service) FinishReading(cmd FinishItemCommand) error {
-	itemID, err := reading.NewItemID(cmd.ItemID)
+func (s *Service) PauseReading(id string) error {
+	item, err := s.findItem(id)
 	if err != nil {
 		return err
 	}
 
-	item, err := s.repo.FindByID(itemID)
+	if err := item.Pause(time.Now()); err != nil {
+		return err
+	}
+
+	return s.describe("pause", item).Update(item)
+}
+
+func (s *Service) ResumeReading(id string) error {
+	item, err := s.findItem(id)
 	if err != nil {
-		return fmt.Errorf("item not found: %w", err)
+		return err
 	}
 
-	rating, err := reading.NewRating(cmd.Rating)
+	if err := item.Resume(time.Now()); err != nil {
+		return err
+	}
+
+	return s.describe("resume", item).Update(item)
+}
+
+func (s *Service) AbandonReading(cmd AbandonItemCommand) error {
+	item, err := s.findItem(cmd.ItemID)
 	if err != nil {
 		return err
 	}
 
-	if err := item.Finish(time.Now(), rating); err != nil {
+	if err := item.Abandon(time.Now(), cmd.Reason); err != nil {
 		return err
 	}
 
-	if cmd.Review != "" {
-		item.Metadata.Review = cmd.Review
+	return s.describe("abandon", item).Update(item)
+}
+
+func (s *Service) RereadItem(id string) error {
+	item, err := s.findItem(id)
+	if err != nil {
+		return err
+	}
+
+	if err := item.Reread(time.Now()); err != nil {
+		return err
 	}
 
-	return s.repo.Update(item)
+	return s.describe("reread", item).Update(item)
 }
 
 func (s *Service) DeleteItem(id string) error {
-	itemID, err := reading.NewItemID(id)
+	item, err := s.findItem(id)
 	if err != nil {
 		return err
 	}
 
-	return s.repo.Delete(itemID)
+	return s.describe("delete", item).Delete(item.ID)
 }
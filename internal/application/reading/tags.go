@@ -0,0 +1,186 @@
+package reading
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+)
+
+// TagDTO is a tag with how many items use it. Count is the number of items
+// tagged with it; Total also counts the items tagged with a nested tag, so
+// a parent only used through its children has a Count of 0.
+type TagDTO struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	Total int    `json:"total"`
+}
+
+// RenameTagCommand renames a tag and the tags nested under it.
+type RenameTagCommand struct {
+	From string
+	To   string
+}
+
+// MergeTagsCommand replaces several tags, and the tags nested under them,
+// with one.
+type MergeTagsCommand struct {
+	From []string
+	To   string
+}
+
+// Tags lists every tag in use and the parents of nested tags, sorted by
+// name so nested tags follow their parent.
+func (s *Service) Tags() ([]TagDTO, error) {
+	items, err := s.repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load items: %w", err)
+	}
+
+	counts := make(map[reading.Tag]*TagDTO)
+	for _, item := range items {
+		seen := make(map[reading.Tag]bool)
+		for _, tag := range item.Tags {
+			for level := tag; level != ""; level = level.Parent() {
+				dto, ok := counts[level]
+				if !ok {
+					dto = &TagDTO{Name: string(level)}
+					counts[level] = dto
+				}
+				if level == tag {
+					dto.Count++
+				}
+				if !seen[level] {
+					dto.Total++
+					seen[level] = true
+				}
+			}
+		}
+	}
+
+	tags := []TagDTO{}
+	for _, dto := range counts {
+		tags = append(tags, *dto)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	return tags, nil
+}
+
+// RenameTag renames a tag on every item in a single write and returns the
+// number of items changed. Renaming onto a tag in use is refused; use
+// MergeTags for that.
+func (s *Service) RenameTag(cmd RenameTagCommand) (int, error) {
+	from, err := reading.NewTag(cmd.From)
+	if err != nil {
+		return 0, err
+	}
+	to, err := reading.NewTag(cmd.To)
+	if err != nil {
+		return 0, err
+	}
+
+	items, err := s.repo.FindAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load items: %w", err)
+	}
+	for _, item := range items {
+		if item.TaggedWith(to) && !from.Includes(to) {
+			return 0, fmt.Errorf("tag %s is already in use, merge the tags instead", to)
+		}
+	}
+
+	return s.replaceTags(items, []reading.Tag{from}, to, "rename-tag", fmt.Sprintf("%s to %s", from, to))
+}
+
+// MergeTags replaces the tags with the target tag on every item in a single
+// write and returns the number of items changed.
+func (s *Service) MergeTags(cmd MergeTagsCommand) (int, error) {
+	to, err := reading.NewTag(cmd.To)
+	if err != nil {
+		return 0, err
+	}
+	from := []reading.Tag{}
+	for _, name := range cmd.From {
+		tag, err := reading.NewTag(name)
+		if err != nil {
+			return 0, err
+		}
+		if tag != to {
+			from = append(from, tag)
+		}
+	}
+	if len(from) == 0 {
+		return 0, fmt.Errorf("nothing to merge into %s", to)
+	}
+
+	items, err := s.repo.FindAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load items: %w", err)
+	}
+	return s.replaceTags(items, from, to, "merge-tags", fmt.Sprintf("%s into %s", joinTags(from), to))
+}
+
+// DeleteTag removes a tag and the tags nested under it from every item in a
+// single write and returns the number of items changed.
+func (s *Service) DeleteTag(name string) (int, error) {
+	tag, err := reading.NewTag(name)
+	if err != nil {
+		return 0, err
+	}
+
+	items, err := s.repo.FindAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load items: %w", err)
+	}
+	return s.replaceTags(items, []reading.Tag{tag}, "", "delete-tag", string(tag))
+}
+
+func (s *Service) replaceTags(items []*reading.Item, from []reading.Tag, to reading.Tag, operation, detail string) (int, error) {
+	changed := []*reading.Item{}
+	for _, item := range items {
+		replaced := false
+		for _, tag := range from {
+			if item.ReplaceTag(tag, to) {
+				replaced = true
+			}
+		}
+		if replaced {
+			changed = append(changed, item)
+		}
+	}
+	if len(changed) == 0 {
+		return 0, fmt.Errorf("no item is tagged %s", joinTags(from))
+	}
+
+	change := reading.Change{Operation: operation, Items: changed, Detail: detail}
+	if err := s.record(change).ReplaceAll(items); err != nil {
+		return 0, fmt.Errorf("failed to save tags: %w", err)
+	}
+	return len(changed), nil
+}
+
+func joinTags(tags []reading.Tag) string {
+	names := ""
+	for i, tag := range tags {
+		if i > 0 {
+			names += ", "
+		}
+		names += string(tag)
+	}
+	return names
+}
+
+// parseTags validates the tags given for an item; a leading # is dropped.
+func parseTags(values []string) ([]reading.Tag, error) {
+	tags := []reading.Tag{}
+	for _, value := range values {
+		tag, err := reading.NewTag(value)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
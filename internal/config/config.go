@@ -1,16 +1,30 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"text/template"
 	"time"
 )
 
+// Storage layouts for the reading list.
+const (
+	// StorageSingleFile keeps every item in gitlife/reading.md.
+	StorageSingleFile = "single"
+	// StorageNotes keeps each item in its own note under gitlife/reading/.
+	StorageNotes = "notes"
+)
+
+// DefaultCommitTemplate describes a write as in "reading: start 'Clean Code'".
+const DefaultCommitTemplate = "reading: {{.Operation}} {{.Subject}}"
+
 type Config struct {
 	// Vault configuration
 	VaultRepo     string
 	VaultPath     string
 	GitLifeFolder string
+	Storage       string
 
 	// Authentication
 	SSHKeyPath string
@@ -20,10 +34,17 @@ type Config struct {
 	GitUserEmail string
 
 	// Behavior
-	AutoSync      bool
-	AutoCommit    bool
-	SyncInterval  time.Duration
-	CommitMessage string
+	AutoSync     bool
+	AutoCommit   bool
+	SyncInterval time.Duration
+
+	// CommitTemplate is the text/template of the message of the commit
+	// made for each write, given the operation and the items it touched.
+	CommitTemplate string
+
+	// NextWeights overrides the weights of the "what to read next" score,
+	// e.g. "priority=2,age=0.5".
+	NextWeights string
 
 	// Application
 	Debug bool
@@ -35,6 +56,7 @@ func LoadFromEnv() *Config {
 		VaultRepo:     getEnv("GITLIFE_VAULT_REPO", ""),
 		VaultPath:     getEnv("GITLIFE_VAULT_PATH", "./vault"),
 		GitLifeFolder: getEnv("GITLIFE_FOLDER", "gitlife"),
+		Storage:       getEnv("GITLIFE_STORAGE", StorageSingleFile),
 
 		// Authentication
 		SSHKeyPath: getEnv("GITLIFE_SSH_KEY_PATH", expandHome("~/.ssh/id_rsa")),
@@ -44,10 +66,13 @@ func LoadFromEnv() *Config {
 		GitUserEmail: getEnv("GITLIFE_GIT_USER_EMAIL", "gitlife@local"),
 
 		// Behavior
-		AutoSync:      getBoolEnv("GITLIFE_AUTO_SYNC", true),
-		AutoCommit:    getBoolEnv("GITLIFE_AUTO_COMMIT", true),
-		SyncInterval:  getDurationEnv("GITLIFE_SYNC_INTERVAL", 5*time.Minute),
-		CommitMessage: getEnv("GITLIFE_COMMIT_MESSAGE", "Update from GitLife"),
+		AutoSync:     getBoolEnv("GITLIFE_AUTO_SYNC", true),
+		AutoCommit:   getBoolEnv("GITLIFE_AUTO_COMMIT", true),
+		SyncInterval: getDurationEnv("GITLIFE_SYNC_INTERVAL", 5*time.Minute),
+		// GITLIFE_COMMIT_MESSAGE was a fixed message, which still works as
+		// a template
+		CommitTemplate: getEnv("GITLIFE_COMMIT_TEMPLATE", getEnv("GITLIFE_COMMIT_MESSAGE", DefaultCommitTemplate)),
+		NextWeights:    getEnv("GITLIFE_NEXT_WEIGHTS", ""),
 
 		// Application
 		Debug: getBoolEnv("GITLIFE_DEBUG", false),
@@ -55,7 +80,14 @@ func LoadFromEnv() *Config {
 }
 
 func (c *Config) Validate() error {
-	// Validation will be added as needed
+	switch c.Storage {
+	case StorageSingleFile, StorageNotes:
+	default:
+		return fmt.Errorf("invalid storage %q (expected %q or %q)", c.Storage, StorageSingleFile, StorageNotes)
+	}
+	if _, err := template.New("commit").Parse(c.CommitTemplate); err != nil {
+		return fmt.Errorf("invalid commit template: %w", err)
+	}
 	return nil
 }
 
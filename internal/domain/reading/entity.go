@@ -1,10 +1,18 @@
 package reading
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
+// idLength is the number of hex characters kept from the ID hash.
+const idLength = 8
+
 type Item struct {
 	ID       ItemID
 	Title    Title
@@ -15,7 +23,13 @@ type Item struct {
 	Tags     []Tag
 	Progress *Progress
 	Rating   *Rating
-	Metadata Metadata
+	Sessions []Session
+	// History holds the earlier reads of an item that was read again; the
+	// current read lives in Metadata and Rating.
+	History    []ReadThrough
+	Highlights []Highlight
+	Metadata   Metadata
+	Extra      Extra
 }
 
 func NewItem(title Title, author Author, itemType ItemType) (*Item, error) {
@@ -23,8 +37,10 @@ func NewItem(title Title, author Author, itemType ItemType) (*Item, error) {
 		return nil, errors.New("invalid item type")
 	}
 
+	now := time.Now()
+
 	return &Item{
-		ID:       ItemID(generateID(string(title), string(author))),
+		ID:       GenerateID(string(title), string(author), now.Format(time.RFC3339Nano)),
 		Title:    title,
 		Author:   author,
 		Type:     itemType,
@@ -32,7 +48,7 @@ func NewItem(title Title, author Author, itemType ItemType) (*Item, error) {
 		Priority: PriorityMedium,
 		Tags:     []Tag{},
 		Metadata: Metadata{
-			Added: time.Now(),
+			Added: now,
 		},
 	}, nil
 }
@@ -41,10 +57,16 @@ func (i *Item) Start(date time.Time) error {
 	if i.Status != StatusToRead {
 		return errors.New("can only start items with 'to-read' status")
 	}
+	if err := i.transitionTo(StatusReading); err != nil {
+		return err
+	}
 
-	i.Status = StatusReading
 	i.Metadata.Started = &date
-	i.Progress = &Progress{Percentage: 0}
+	if i.Progress == nil {
+		i.Progress = &Progress{}
+	}
+	i.Progress.Percentage = 0
+	i.Progress.CurrentPage = 0
 
 	return nil
 }
@@ -53,8 +75,10 @@ func (i *Item) Finish(date time.Time, rating Rating) error {
 	if i.Status != StatusReading {
 		return errors.New("can only finish items with 'reading' status")
 	}
+	if err := i.transitionTo(StatusDone); err != nil {
+		return err
+	}
 
-	i.Status = StatusDone
 	i.Metadata.Finished = &date
 	i.Rating = &rating
 	if i.Progress != nil {
@@ -73,6 +97,68 @@ func (i *Item) UpdateProgress(progress *Progress) error {
 	return nil
 }
 
+// LogSession updates the progress and records it as a reading session.
+// minutes is how long the session took, 0 when unknown.
+func (i *Item) LogSession(date time.Time, progress *Progress, minutes int) error {
+	if minutes < 0 {
+		return errors.New("session duration cannot be negative")
+	}
+
+	fromPage := 0
+	if i.Progress != nil && progress.CurrentPage > 0 {
+		fromPage = i.Progress.CurrentPage
+	}
+
+	if err := i.UpdateProgress(progress); err != nil {
+		return err
+	}
+
+	i.Sessions = append(i.Sessions, Session{
+		Date:       date,
+		FromPage:   fromPage,
+		ToPage:     progress.CurrentPage,
+		Percentage: progress.Percentage,
+		Minutes:    minutes,
+	})
+	return nil
+}
+
+// AddHighlight saves a passage of the item. Text and note are kept on a
+// single line; page is 0 when unknown.
+func (i *Item) AddHighlight(date time.Time, text string, page int, note string) (Highlight, error) {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return Highlight{}, errors.New("highlight text cannot be empty")
+	}
+	if page < 0 {
+		return Highlight{}, errors.New("highlight page cannot be negative")
+	}
+	if i.Progress != nil && i.Progress.TotalPages > 0 && page > i.Progress.TotalPages {
+		return Highlight{}, &PositionError{Position: page, Total: i.Progress.TotalPages}
+	}
+
+	highlight := Highlight{
+		Added: date,
+		Text:  text,
+		Page:  page,
+		Note:  strings.Join(strings.Fields(note), " "),
+	}
+	i.Highlights = append(i.Highlights, highlight)
+	return highlight, nil
+}
+
+// HasHighlight reports whether a passage with the same text (ignoring case
+// and spacing) was already saved.
+func (i *Item) HasHighlight(text string) bool {
+	text = strings.Join(strings.Fields(text), " ")
+	for _, highlight := range i.Highlights {
+		if strings.EqualFold(highlight.Text, text) {
+			return true
+		}
+	}
+	return false
+}
+
 func (i *Item) CanTransitionTo(status Status) bool {
 	if !status.IsValid() {
 		return false
@@ -80,16 +166,287 @@ func (i *Item) CanTransitionTo(status Status) bool {
 
 	switch i.Status {
 	case StatusToRead:
-		return status == StatusReading
+		return status == StatusReading || status == StatusAbandoned
 	case StatusReading:
-		return status == StatusDone || status == StatusToRead
+		return status == StatusDone || status == StatusToRead || status == StatusPaused || status == StatusAbandoned
+	case StatusPaused:
+		return status == StatusReading || status == StatusToRead || status == StatusAbandoned
 	case StatusDone:
 		return status == StatusReading
+	case StatusAbandoned:
+		return status == StatusReading || status == StatusToRead
 	default:
 		return false
 	}
 }
 
+// Pause puts a reading item on hold, keeping its progress.
+func (i *Item) Pause(date time.Time) error {
+	if i.Status != StatusReading {
+		return errors.New("can only pause items with 'reading' status")
+	}
+	if err := i.transitionTo(StatusPaused); err != nil {
+		return err
+	}
+
+	i.Metadata.Paused = &date
+	return nil
+}
+
+// Resume picks up a paused or abandoned item where it was left. An item
+// abandoned before it was started counts as started on date.
+func (i *Item) Resume(date time.Time) error {
+	if i.Status != StatusPaused && i.Status != StatusAbandoned {
+		return errors.New("can only resume items with 'paused' or 'abandoned' status")
+	}
+	if err := i.transitionTo(StatusReading); err != nil {
+		return err
+	}
+
+	if i.Metadata.Started == nil {
+		i.Metadata.Started = &date
+	}
+	i.Metadata.Paused = nil
+	i.Metadata.Abandoned = nil
+	i.Metadata.Reason = ""
+	return nil
+}
+
+// Abandon records that the item was given up, and why.
+func (i *Item) Abandon(date time.Time, reason string) error {
+	if err := i.transitionTo(StatusAbandoned); err != nil {
+		return err
+	}
+
+	i.Metadata.Paused = nil
+	i.Metadata.Abandoned = &date
+	i.Metadata.Reason = reason
+	return nil
+}
+
+// Reread starts a finished item over. The finished read is moved to the
+// history with its rating and review; progress starts from zero and the
+// total length is kept.
+func (i *Item) Reread(date time.Time) error {
+	if i.Status != StatusDone {
+		return errors.New("can only re-read items with 'done' status")
+	}
+	if err := i.transitionTo(StatusReading); err != nil {
+		return err
+	}
+
+	i.History = append(i.History, i.currentRead())
+	i.Rating = nil
+	i.Metadata.Review = ""
+	i.Metadata.Started = &date
+	i.Metadata.Finished = nil
+	if i.Progress == nil {
+		i.Progress = &Progress{}
+	}
+	i.Progress.Percentage = 0
+	i.Progress.CurrentPage = 0
+	return nil
+}
+
+// Restore puts an item in the status and dates it had in another
+// application, without going through the status transitions. finished is
+// only kept for done items.
+func (i *Item) Restore(status Status, started, finished *time.Time) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid status: %s", status)
+	}
+
+	i.Status = status
+	i.Metadata.Started = started
+	i.Metadata.Finished = nil
+	if status == StatusDone {
+		i.Metadata.Finished = finished
+		if i.Progress != nil {
+			i.Progress.Percentage = 100
+		}
+	}
+	return nil
+}
+
+// Merge folds a duplicate into the item. Tags, sessions, earlier reads and
+// highlights are combined and the earliest added date is kept; the status,
+// dates and progress come from whichever copy got further. Notes and
+// reviews are joined and the fields the item is missing are taken over.
+func (i *Item) Merge(other *Item) {
+	if added := other.Metadata.Added; !added.IsZero() && (i.Metadata.Added.IsZero() || added.Before(i.Metadata.Added)) {
+		i.Metadata.Added = added
+	}
+	for _, tag := range other.Tags {
+		i.AddTag(tag)
+	}
+	if priorityRank(other.Priority) > priorityRank(i.Priority) {
+		i.Priority = other.Priority
+	}
+
+	if other.isFurtherThan(i) {
+		i.Status = other.Status
+		i.Metadata.Started = other.Metadata.Started
+		i.Metadata.Finished = other.Metadata.Finished
+		i.Metadata.Paused = other.Metadata.Paused
+		i.Metadata.Abandoned = other.Metadata.Abandoned
+		i.Metadata.Reason = other.Metadata.Reason
+		if other.Progress != nil {
+			total := 0
+			if i.Progress != nil {
+				total = i.Progress.TotalPages
+			}
+			progress := *other.Progress
+			if progress.TotalPages == 0 && progress.CurrentPage <= total {
+				progress.TotalPages = total
+			}
+			i.Progress = &progress
+		}
+		if other.Rating != nil {
+			i.Rating = other.Rating
+		}
+	} else if other.Progress != nil && other.Progress.TotalPages > 0 && (i.Progress == nil || i.Progress.TotalPages == 0) {
+		_ = i.SetTotalPages(other.Progress.TotalPages)
+	}
+	if i.Rating == nil && i.Status == StatusDone && other.Status == StatusDone {
+		i.Rating = other.Rating
+	}
+
+	i.Sessions = append(i.Sessions, other.Sessions...)
+	sort.SliceStable(i.Sessions, func(a, b int) bool {
+		return i.Sessions[a].Date.Before(i.Sessions[b].Date)
+	})
+	i.History = append(i.History, other.History...)
+	for _, highlight := range other.Highlights {
+		if !i.HasHighlight(highlight.Text) {
+			i.Highlights = append(i.Highlights, highlight)
+		}
+	}
+
+	if i.Author == "" || i.Author == "Unknown" {
+		i.Author = other.Author
+	}
+	i.Metadata.Notes = joinText(i.Metadata.Notes, other.Metadata.Notes, "; ")
+	i.Metadata.Review = joinText(i.Metadata.Review, other.Metadata.Review, "; ")
+	fill := func(value *string, from string) {
+		if *value == "" {
+			*value = from
+		}
+	}
+	fill(&i.Metadata.URL, other.Metadata.URL)
+	fill(&i.Metadata.ISBN, other.Metadata.ISBN)
+	fill(&i.Metadata.DOI, other.Metadata.DOI)
+	fill(&i.Metadata.Venue, other.Metadata.Venue)
+	if i.Metadata.Year == 0 {
+		i.Metadata.Year = other.Metadata.Year
+	}
+	if len(i.Metadata.Authors) == 0 {
+		i.Metadata.Authors = other.Metadata.Authors
+	}
+
+	for _, property := range other.Extra.Properties {
+		if !i.hasProperty(property.Key) {
+			i.Extra.Properties = append(i.Extra.Properties, property)
+		}
+	}
+	i.Extra.Body = joinText(i.Extra.Body, other.Extra.Body, "\n\n")
+}
+
+// isFurtherThan reports whether the item got further than other: a later
+// status in the reading flow or, with the same status, more progress.
+func (i *Item) isFurtherThan(other *Item) bool {
+	if i.Status != other.Status {
+		return statusRank(i.Status) > statusRank(other.Status)
+	}
+	return i.percentage() > other.percentage()
+}
+
+func (i *Item) percentage() int {
+	if i.Progress == nil {
+		return 0
+	}
+	return i.Progress.Percentage
+}
+
+func (i *Item) hasProperty(key string) bool {
+	for _, property := range i.Extra.Properties {
+		if property.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// statusRank orders statuses by how far along the reading flow they are.
+func statusRank(status Status) int {
+	switch status {
+	case StatusAbandoned:
+		return 1
+	case StatusPaused:
+		return 2
+	case StatusReading:
+		return 3
+	case StatusDone:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// joinText joins two texts with separator, skipping one that is empty or
+// the same as the other.
+func joinText(text, other, separator string) string {
+	text, other = strings.TrimSpace(text), strings.TrimSpace(other)
+	switch {
+	case other == "" || other == text:
+		return text
+	case text == "":
+		return other
+	default:
+		return text + separator + other
+	}
+}
+
+// ReadThroughs returns every completed read, oldest first, including the
+// current one once it is finished.
+func (i *Item) ReadThroughs() []ReadThrough {
+	reads := append([]ReadThrough{}, i.History...)
+	if i.Status == StatusDone {
+		reads = append(reads, i.currentRead())
+	}
+	return reads
+}
+
+// LatestRating returns the rating of the most recent read that has one, so
+// an item being read again keeps showing its last verdict.
+func (i *Item) LatestRating() *Rating {
+	if i.Rating != nil && i.Rating.Value() > 0 {
+		return i.Rating
+	}
+	for k := len(i.History) - 1; k >= 0; k-- {
+		if rating := i.History[k].Rating; rating != nil && rating.Value() > 0 {
+			return rating
+		}
+	}
+	return nil
+}
+
+func (i *Item) currentRead() ReadThrough {
+	return ReadThrough{
+		Started:  i.Metadata.Started,
+		Finished: i.Metadata.Finished,
+		Rating:   i.Rating,
+		Review:   i.Metadata.Review,
+	}
+}
+
+func (i *Item) transitionTo(status Status) error {
+	if !i.CanTransitionTo(status) {
+		return fmt.Errorf("cannot move from '%s' to '%s'", i.Status, status)
+	}
+	i.Status = status
+	return nil
+}
+
 func (i *Item) AddTag(tag Tag) {
 	for _, existing := range i.Tags {
 		if existing == tag {
@@ -99,6 +456,67 @@ func (i *Item) AddTag(tag Tag) {
 	i.Tags = append(i.Tags, tag)
 }
 
+func (i *Item) HasTag(tag Tag) bool {
+	for _, existing := range i.Tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// TaggedWith reports whether the item has the tag or one nested under it.
+func (i *Item) TaggedWith(tag Tag) bool {
+	for _, existing := range i.Tags {
+		if tag.Includes(existing) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReplaceTag renames from, and the tags nested under it, to to: with from
+// programming and to dev, programming/go becomes dev/go. An empty to removes
+// them. It reports whether the tags changed.
+func (i *Item) ReplaceTag(from, to Tag) bool {
+	kept := []Tag{}
+	for _, tag := range i.Tags {
+		if !from.Includes(tag) {
+			kept = append(kept, tag)
+		}
+	}
+
+	changed := false
+	tags := []Tag{}
+	for _, tag := range i.Tags {
+		if !from.Includes(tag) {
+			tags = append(tags, tag)
+			continue
+		}
+		changed = true
+		if to == "" {
+			continue
+		}
+		renamed := to + tag[len(from):]
+		if !containsTag(kept, renamed) && !containsTag(tags, renamed) {
+			tags = append(tags, renamed)
+		}
+	}
+	if changed {
+		i.Tags = tags
+	}
+	return changed
+}
+
+func containsTag(tags []Tag, tag Tag) bool {
+	for _, existing := range tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
 func (i *Item) RemoveTag(tag Tag) {
 	filtered := []Tag{}
 	for _, existing := range i.Tags {
@@ -109,6 +527,41 @@ func (i *Item) RemoveTag(tag Tag) {
 	i.Tags = filtered
 }
 
+// Rename changes the title and author. The ID is kept so references to the
+// item survive retitling.
+func (i *Item) Rename(title Title, author Author) {
+	i.Title = title
+	i.Author = author
+}
+
+func (i *Item) SetType(itemType ItemType) error {
+	if !itemType.IsValid() {
+		return errors.New("invalid item type")
+	}
+	i.Type = itemType
+	return nil
+}
+
+// SetTotalPages sets the length of the item in its progress unit. The
+// percentage is derived again when the current position is known.
+func (i *Item) SetTotalPages(pages int) error {
+	if pages < 0 {
+		return errors.New("total pages cannot be negative")
+	}
+	if i.Progress == nil {
+		i.Progress = &Progress{}
+	}
+	if pages > 0 && i.Progress.CurrentPage > pages {
+		return &PositionError{Position: i.Progress.CurrentPage, Total: pages}
+	}
+
+	i.Progress.TotalPages = pages
+	if pages > 0 && i.Progress.CurrentPage > 0 {
+		i.Progress.Percentage = i.Progress.CurrentPage * 100 / pages
+	}
+	return nil
+}
+
 func (i *Item) SetPriority(priority Priority) error {
 	if !priority.IsValid() {
 		return errors.New("invalid priority")
@@ -117,18 +570,15 @@ func (i *Item) SetPriority(priority Priority) error {
 	return nil
 }
 
-func generateID(title, author string) string {
-	return sanitizeForID(title + "-" + author)
-}
-
-func sanitizeForID(s string) string {
-	result := ""
-	for _, r := range s {
-		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' {
-			result += string(r)
-		} else if r == ' ' {
-			result += "-"
-		}
+// GenerateID derives a short, stable identifier by hashing the given seeds.
+// It is only used when an item is created (or first read without a
+// persisted ID); afterwards the ID is stored with the item and never
+// recomputed.
+func GenerateID(seeds ...string) ItemID {
+	h := sha1.New()
+	for _, seed := range seeds {
+		h.Write([]byte(seed))
+		h.Write([]byte{0})
 	}
-	return result
+	return ItemID(hex.EncodeToString(h.Sum(nil))[:idLength])
 }
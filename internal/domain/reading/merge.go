@@ -0,0 +1,331 @@
+package reading
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Conflict is a property both sides of a merge changed, each in its own
+// way. The merged item keeps our value.
+type Conflict struct {
+	ID     ItemID
+	Title  Title
+	Field  string
+	Ours   string
+	Theirs string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s (%s): %s is %q here and %q on the other side", c.Title, c.ID, c.Field, c.Ours, c.Theirs)
+}
+
+// MergeVersions merges two versions of a reading list, ours and theirs,
+// that both started from base. Items are matched by ID: an item added on
+// either side is kept, one deleted on a side and left alone on the other is
+// removed, and one changed on both sides is merged with MergeItem. Deleting
+// an item the other side changed is a conflict and keeps the item.
+func MergeVersions(base, ours, theirs []*Item) ([]*Item, []Conflict) {
+	baseByID, oursByID, theirsByID := itemsByID(base), itemsByID(ours), itemsByID(theirs)
+
+	merged := []*Item{}
+	conflicts := []Conflict{}
+	for _, item := range ours {
+		original := baseByID[item.ID]
+		other, changedThere := theirsByID[item.ID]
+		switch {
+		case changedThere:
+			result, found := MergeItem(original, item, other)
+			merged = append(merged, result)
+			conflicts = append(conflicts, found...)
+		case original == nil:
+			merged = append(merged, item)
+		case !reflect.DeepEqual(item, original):
+			merged = append(merged, item)
+			conflicts = append(conflicts, Conflict{ID: item.ID, Title: item.Title, Field: "item", Ours: "changed", Theirs: "deleted"})
+		}
+	}
+	for _, item := range theirs {
+		if _, ok := oursByID[item.ID]; ok {
+			continue
+		}
+		original := baseByID[item.ID]
+		switch {
+		case original == nil:
+			merged = append(merged, item)
+		case !reflect.DeepEqual(item, original):
+			merged = append(merged, item)
+			conflicts = append(conflicts, Conflict{ID: item.ID, Title: item.Title, Field: "item", Ours: "deleted", Theirs: "changed"})
+		}
+	}
+	return merged, conflicts
+}
+
+// MergeItem merges two versions of an item that both started from base,
+// property by property: a property changed on one side only takes that
+// change. When both sides changed it, tags, sessions, earlier reads and
+// highlights are combined, progress keeps whichever side got further and
+// the status comes from the side that changed it last; any other property
+// is a conflict. Without a base, the item was added on both sides and the
+// two are folded together as duplicates.
+func MergeItem(base, ours, theirs *Item) (*Item, []Conflict) {
+	merged := *ours
+	if base == nil {
+		merged.Merge(theirs)
+		return &merged, nil
+	}
+
+	m := &itemMerge{base: base, ours: ours, theirs: theirs}
+	merged.Title = pickValue(m, "title", base.Title, ours.Title, theirs.Title)
+	merged.Author = pickValue(m, "author", base.Author, ours.Author, theirs.Author)
+	merged.Type = pickValue(m, "type", base.Type, ours.Type, theirs.Type)
+	merged.Priority = pickValue(m, "priority", base.Priority, ours.Priority, theirs.Priority)
+	merged.Tags = mergeList(base.Tags, ours.Tags, theirs.Tags, func(a, b Tag) bool { return a == b })
+
+	m.mergeStatus(&merged)
+	merged.Progress = m.mergeProgress(merged.Type.ProgressUnit())
+	merged.Rating = pick(m, "rating", base.Rating, ours.Rating, theirs.Rating, formatRating)
+
+	merged.Sessions = mergeList(base.Sessions, ours.Sessions, theirs.Sessions, func(a, b Session) bool {
+		return a.Date.Equal(b.Date) && a.FromPage == b.FromPage && a.ToPage == b.ToPage &&
+			a.Percentage == b.Percentage && a.Minutes == b.Minutes
+	})
+	sort.SliceStable(merged.Sessions, func(a, b int) bool {
+		return merged.Sessions[a].Date.Before(merged.Sessions[b].Date)
+	})
+	merged.History = mergeList(base.History, ours.History, theirs.History, func(a, b ReadThrough) bool {
+		return formatDate(a.Started) == formatDate(b.Started) && formatDate(a.Finished) == formatDate(b.Finished)
+	})
+	merged.Highlights = mergeList(base.Highlights, ours.Highlights, theirs.Highlights, func(a, b Highlight) bool {
+		return a.Text == b.Text
+	})
+
+	merged.Metadata.Added = pick(m, "added", base.Metadata.Added, ours.Metadata.Added, theirs.Metadata.Added, func(t time.Time) string {
+		return formatDate(&t)
+	})
+	merged.Metadata.URL = pickValue(m, "url", base.Metadata.URL, ours.Metadata.URL, theirs.Metadata.URL)
+	merged.Metadata.ISBN = pickValue(m, "isbn", base.Metadata.ISBN, ours.Metadata.ISBN, theirs.Metadata.ISBN)
+	merged.Metadata.Notes = pickValue(m, "notes", base.Metadata.Notes, ours.Metadata.Notes, theirs.Metadata.Notes)
+	merged.Metadata.Review = pickValue(m, "review", base.Metadata.Review, ours.Metadata.Review, theirs.Metadata.Review)
+	merged.Metadata.DOI = pickValue(m, "doi", base.Metadata.DOI, ours.Metadata.DOI, theirs.Metadata.DOI)
+	merged.Metadata.Venue = pickValue(m, "venue", base.Metadata.Venue, ours.Metadata.Venue, theirs.Metadata.Venue)
+	merged.Metadata.Year = pickValue(m, "year", base.Metadata.Year, ours.Metadata.Year, theirs.Metadata.Year)
+	merged.Metadata.Authors = pick(m, "authors", base.Metadata.Authors, ours.Metadata.Authors, theirs.Metadata.Authors, func(authors []string) string {
+		return strings.Join(authors, "; ")
+	})
+
+	merged.Extra.Properties = m.mergeProperties()
+	merged.Extra.Body = pickValue(m, "body", base.Extra.Body, ours.Extra.Body, theirs.Extra.Body)
+
+	return &merged, m.conflicts
+}
+
+type itemMerge struct {
+	base, ours, theirs *Item
+	conflicts          []Conflict
+}
+
+func (m *itemMerge) conflict(field, ours, theirs string) {
+	m.conflicts = append(m.conflicts, Conflict{ID: m.ours.ID, Title: m.ours.Title, Field: field, Ours: ours, Theirs: theirs})
+}
+
+// pick returns the value of the side that changed it, comparing values by
+// their text. Two different changes are a conflict and keep ours.
+func pick[T any](m *itemMerge, field string, base, ours, theirs T, format func(T) string) T {
+	b, o, t := format(base), format(ours), format(theirs)
+	switch {
+	case o == t || t == b:
+		return ours
+	case o == b:
+		return theirs
+	}
+	m.conflict(field, o, t)
+	return ours
+}
+
+func pickValue[T comparable](m *itemMerge, field string, base, ours, theirs T) T {
+	return pick(m, field, base, ours, theirs, func(value T) string { return fmt.Sprint(value) })
+}
+
+// mergeStatus takes the status and its dates from the side that changed
+// them. When both did, the side whose status changed last wins, or the one
+// that got further on the same day; with the same status on both sides the
+// dates are merged one by one.
+func (m *itemMerge) mergeStatus(merged *Item) {
+	base, ours, theirs := statusKey(m.base), statusKey(m.ours), statusKey(m.theirs)
+	from := m.ours
+	switch {
+	case ours == theirs || theirs == base:
+	case ours == base:
+		from = m.theirs
+	case m.ours.Status != m.theirs.Status:
+		oursDate, theirsDate := m.ours.statusDate(), m.theirs.statusDate()
+		if theirsDate.After(oursDate) || (theirsDate.Equal(oursDate) && m.theirs.isFurtherThan(m.ours)) {
+			from = m.theirs
+		}
+	default:
+		merged.Metadata.Started = pick(m, "started", m.base.Metadata.Started, m.ours.Metadata.Started, m.theirs.Metadata.Started, formatDate)
+		merged.Metadata.Finished = pick(m, "finished", m.base.Metadata.Finished, m.ours.Metadata.Finished, m.theirs.Metadata.Finished, formatDate)
+		merged.Metadata.Paused = pick(m, "paused", m.base.Metadata.Paused, m.ours.Metadata.Paused, m.theirs.Metadata.Paused, formatDate)
+		merged.Metadata.Abandoned = pick(m, "abandoned", m.base.Metadata.Abandoned, m.ours.Metadata.Abandoned, m.theirs.Metadata.Abandoned, formatDate)
+		merged.Metadata.Reason = pickValue(m, "reason", m.base.Metadata.Reason, m.ours.Metadata.Reason, m.theirs.Metadata.Reason)
+		return
+	}
+
+	merged.Status = from.Status
+	merged.Metadata.Started = from.Metadata.Started
+	merged.Metadata.Finished = from.Metadata.Finished
+	merged.Metadata.Paused = from.Metadata.Paused
+	merged.Metadata.Abandoned = from.Metadata.Abandoned
+	merged.Metadata.Reason = from.Metadata.Reason
+}
+
+// mergeProgress keeps the progress of the side that got further when both
+// moved on. A total changed in two different ways is a conflict.
+func (m *itemMerge) mergeProgress(unit ProgressUnit) *Progress {
+	base, ours, theirs := m.base.Progress, m.ours.Progress, m.theirs.Progress
+	switch b, o, t := formatProgress(base), formatProgress(ours), formatProgress(theirs); {
+	case o == t || t == b:
+		return ours
+	case o == b:
+		return theirs
+	}
+
+	total := pickValue(m, string(unit), totalOf(base), totalOf(ours), totalOf(theirs))
+	further := ours
+	if ours == nil || (theirs != nil && (theirs.Percentage > ours.Percentage ||
+		(theirs.Percentage == ours.Percentage && theirs.CurrentPage > ours.CurrentPage))) {
+		further = theirs
+	}
+
+	progress := *further
+	if progress.TotalPages != total && (total == 0 || progress.CurrentPage <= total) {
+		progress.TotalPages = total
+		if total > 0 {
+			progress.Percentage = progress.CurrentPage * 100 / total
+		}
+	}
+	return &progress
+}
+
+// mergeProperties merges the properties gitlife does not interpret one key
+// at a time, in the order of our side.
+func (m *itemMerge) mergeProperties() []Property {
+	find := func(item *Item, key string) *Property {
+		for k := range item.Extra.Properties {
+			if item.Extra.Properties[k].Key == key {
+				return &item.Extra.Properties[k]
+			}
+		}
+		return nil
+	}
+	format := func(property *Property) string {
+		if property == nil {
+			return ""
+		}
+		return strings.Join(append([]string{property.Value}, property.Items...), "\n")
+	}
+
+	properties := []Property{}
+	seen := make(map[string]bool)
+	for _, item := range []*Item{m.ours, m.theirs} {
+		for _, property := range item.Extra.Properties {
+			if seen[property.Key] {
+				continue
+			}
+			seen[property.Key] = true
+
+			key := property.Key
+			if merged := pick(m, key, find(m.base, key), find(m.ours, key), find(m.theirs, key), format); merged != nil {
+				properties = append(properties, *merged)
+			}
+		}
+	}
+	return properties
+}
+
+// mergeList merges the entries of a list: an entry stays when both sides
+// kept it or one side added it, and goes when either side removed it.
+func mergeList[T any](base, ours, theirs []T, same func(a, b T) bool) []T {
+	has := func(list []T, entry T) bool {
+		for _, other := range list {
+			if same(other, entry) {
+				return true
+			}
+		}
+		return false
+	}
+
+	merged := make([]T, 0, len(ours))
+	for _, list := range [][]T{ours, theirs} {
+		for _, entry := range list {
+			if has(merged, entry) || (has(base, entry) && !(has(ours, entry) && has(theirs, entry))) {
+				continue
+			}
+			merged = append(merged, entry)
+		}
+	}
+	return merged
+}
+
+// statusDate returns when the status of the item last changed, as far as
+// its dates tell.
+func (i *Item) statusDate() time.Time {
+	var latest time.Time
+	for _, date := range []*time.Time{i.Metadata.Started, i.Metadata.Finished, i.Metadata.Paused, i.Metadata.Abandoned} {
+		if date != nil && date.After(latest) {
+			latest = *date
+		}
+	}
+	return latest
+}
+
+func statusKey(item *Item) string {
+	return strings.Join([]string{
+		string(item.Status),
+		formatDate(item.Metadata.Started),
+		formatDate(item.Metadata.Finished),
+		formatDate(item.Metadata.Paused),
+		formatDate(item.Metadata.Abandoned),
+		item.Metadata.Reason,
+	}, "|")
+}
+
+func itemsByID(items []*Item) map[ItemID]*Item {
+	byID := make(map[ItemID]*Item, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	return byID
+}
+
+func totalOf(progress *Progress) int {
+	if progress == nil {
+		return 0
+	}
+	return progress.TotalPages
+}
+
+func formatProgress(progress *Progress) string {
+	if progress == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d%% %d/%d", progress.Percentage, progress.CurrentPage, progress.TotalPages)
+}
+
+func formatRating(rating *Rating) string {
+	if rating == nil {
+		return ""
+	}
+	return strconv.Itoa(rating.Value())
+}
+
+func formatDate(date *time.Time) string {
+	if date == nil || date.IsZero() {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
@@ -0,0 +1,182 @@
+package reading
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func day(n int) *time.Time {
+	date := time.Date(2025, time.January, n, 0, 0, 0, 0, time.UTC)
+	return &date
+}
+
+// dune returns the item as the base version has it, changed by edits. Every
+// call builds a new item, so the versions of a merge share nothing.
+func dune(edits ...func(*Item)) *Item {
+	item := &Item{
+		ID:       "6632e679",
+		Title:    "Dune",
+		Author:   "Frank Herbert",
+		Type:     TypeBook,
+		Status:   StatusReading,
+		Priority: PriorityMedium,
+		Tags:     []Tag{"scifi", "classic"},
+		Progress: &Progress{Percentage: 30, CurrentPage: 124, TotalPages: 412},
+		Metadata: Metadata{Added: *day(1), Started: day(3)},
+	}
+	for _, edit := range edits {
+		edit(item)
+	}
+	return item
+}
+
+func withTags(tags ...Tag) func(*Item) {
+	return func(item *Item) { item.Tags = tags }
+}
+
+func withNotes(notes string) func(*Item) {
+	return func(item *Item) { item.Metadata.Notes = notes }
+}
+
+func withPage(page int) func(*Item) {
+	return func(item *Item) {
+		item.Progress.CurrentPage = page
+		item.Progress.Percentage = page * 100 / item.Progress.TotalPages
+	}
+}
+
+func TestMergeVersions(t *testing.T) {
+	tests := []struct {
+		name      string
+		base      []*Item
+		ours      []*Item
+		theirs    []*Item
+		want      []*Item
+		conflicts []string
+	}{
+		{
+			name: "edits to different properties are combined",
+			base: []*Item{dune()},
+			ours: []*Item{dune(withTags("scifi", "classic", "space"), func(item *Item) {
+				item.Priority = PriorityHigh
+			})},
+			theirs: []*Item{dune(withTags("scifi", "classic", "favorite"), withNotes("read the appendices"))},
+			want: []*Item{dune(withTags("scifi", "classic", "space", "favorite"), withNotes("read the appendices"), func(item *Item) {
+				item.Priority = PriorityHigh
+			})},
+		},
+		{
+			name:      "the same property changed on both sides keeps ours",
+			base:      []*Item{dune()},
+			ours:      []*Item{dune(withNotes("slow start"))},
+			theirs:    []*Item{dune(withNotes("great world building"))},
+			want:      []*Item{dune(withNotes("slow start"))},
+			conflicts: []string{"notes"},
+		},
+		{
+			name:   "an item added on both sides is folded together",
+			base:   []*Item{},
+			ours:   []*Item{dune()},
+			theirs: []*Item{dune(withTags("favorite"), withNotes("gift from Ana"))},
+			want:   []*Item{dune(withTags("scifi", "classic", "favorite"), withNotes("gift from Ana"))},
+		},
+		{
+			name:      "an item deleted here and edited there is kept",
+			base:      []*Item{dune()},
+			ours:      []*Item{},
+			theirs:    []*Item{dune(withPage(200))},
+			want:      []*Item{dune(withPage(200))},
+			conflicts: []string{"item"},
+		},
+		{
+			name:   "an item deleted there and left alone here is removed",
+			base:   []*Item{dune()},
+			ours:   []*Item{dune()},
+			theirs: []*Item{},
+			want:   []*Item{},
+		},
+		{
+			name:   "a tag removed on one side and another added on the other",
+			base:   []*Item{dune()},
+			ours:   []*Item{dune(withTags("scifi"))},
+			theirs: []*Item{dune(withTags("scifi", "classic", "favorite"))},
+			want:   []*Item{dune(withTags("scifi", "favorite"))},
+		},
+		{
+			name: "the status comes from the side that changed it and progress from the one further on",
+			base: []*Item{dune()},
+			ours: []*Item{dune(withPage(200))},
+			theirs: []*Item{dune(withPage(150), func(item *Item) {
+				item.Status = StatusPaused
+				item.Metadata.Paused = day(5)
+			})},
+			want: []*Item{dune(withPage(200), func(item *Item) {
+				item.Status = StatusPaused
+				item.Metadata.Paused = day(5)
+			})},
+		},
+		{
+			name: "a status changed on both sides comes from the latest change",
+			base: []*Item{dune()},
+			ours: []*Item{dune(withPage(412), func(item *Item) {
+				item.Status = StatusDone
+				item.Metadata.Finished = day(9)
+			})},
+			theirs: []*Item{dune(withPage(150), func(item *Item) {
+				item.Status = StatusPaused
+				item.Metadata.Paused = day(5)
+			})},
+			want: []*Item{dune(withPage(412), func(item *Item) {
+				item.Status = StatusDone
+				item.Metadata.Finished = day(9)
+			})},
+		},
+		{
+			name:      "a total changed in two ways is a conflict",
+			base:      []*Item{dune()},
+			ours:      []*Item{dune(func(item *Item) { item.Progress.TotalPages = 400 })},
+			theirs:    []*Item{dune(func(item *Item) { item.Progress.TotalPages = 420 })},
+			want:      []*Item{dune(func(item *Item) { item.Progress.TotalPages = 400 })},
+			conflicts: []string{"pages"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts := MergeVersions(tt.base, tt.ours, tt.theirs)
+
+			if got, want := summarize(merged), summarize(tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("merged items differ\n got: %q\nwant: %q", got, want)
+			}
+
+			fields := []string{}
+			for _, conflict := range conflicts {
+				fields = append(fields, conflict.Field)
+			}
+			if tt.conflicts == nil {
+				tt.conflicts = []string{}
+			}
+			if !reflect.DeepEqual(fields, tt.conflicts) {
+				t.Errorf("conflicts = %v, want %v", conflicts, tt.conflicts)
+			}
+		})
+	}
+}
+
+// summarize writes each item as a line with the properties a merge
+// touches, so empty and nil lists compare the same.
+func summarize(items []*Item) []string {
+	lines := []string{}
+	for _, item := range items {
+		tags := ""
+		for _, tag := range item.Tags {
+			tags += " #" + string(tag)
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s | %s | %s | %s | notes: %s |%s",
+			item.ID, item.Title, item.Priority, statusKey(item), formatProgress(item.Progress),
+			formatRating(item.Rating), item.Metadata.Notes, tags))
+	}
+	return lines
+}
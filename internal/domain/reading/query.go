@@ -0,0 +1,168 @@
+package reading
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Matches reports whether item passes every filter of the options. All
+// tags must be present on the item, directly or through a nested tag.
+func (o QueryOptions) Matches(item *Item) bool {
+	if o.Status != nil && item.Status != *o.Status {
+		return false
+	}
+	if o.Priority != nil && item.Priority != *o.Priority {
+		return false
+	}
+	if o.Type != nil && item.Type != *o.Type {
+		return false
+	}
+
+	for _, tag := range o.Tags {
+		if !item.TaggedWith(tag) {
+			return false
+		}
+	}
+
+	if o.Search != "" && !item.ContainsText(o.Search) {
+		return false
+	}
+
+	if o.Filter != nil && !o.Filter(item) {
+		return false
+	}
+
+	return true
+}
+
+// Query filters, sorts and paginates items. It returns the requested page
+// together with the number of items matching before pagination.
+func Query(items []*Item, options QueryOptions) ([]*Item, int) {
+	matched := []*Item{}
+	for _, item := range items {
+		if options.Matches(item) {
+			matched = append(matched, item)
+		}
+	}
+
+	if options.SortBy != "" {
+		SortItems(matched, options.SortBy, options.Descending)
+	}
+
+	total := len(matched)
+	if options.Offset > 0 {
+		if options.Offset >= len(matched) {
+			return []*Item{}, total
+		}
+		matched = matched[options.Offset:]
+	}
+	if options.Limit > 0 && options.Limit < len(matched) {
+		matched = matched[:options.Limit]
+	}
+
+	return matched, total
+}
+
+// SortItems orders items by key, keeping the storage order for ties. Items
+// without a value for key (no start date, no rating...) always come last.
+func SortItems(items []*Item, key SortKey, descending bool) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, aok := sortValue(items[i], key)
+		b, bok := sortValue(items[j], key)
+		if !aok || !bok {
+			return aok && !bok
+		}
+
+		cmp := compareValues(a, b)
+		if descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+}
+
+func sortValue(item *Item, key SortKey) (interface{}, bool) {
+	switch key {
+	case SortAdded:
+		return item.Metadata.Added, !item.Metadata.Added.IsZero()
+	case SortStarted:
+		if item.Metadata.Started == nil {
+			return nil, false
+		}
+		return *item.Metadata.Started, true
+	case SortFinished:
+		if item.Metadata.Finished == nil {
+			return nil, false
+		}
+		return *item.Metadata.Finished, true
+	case SortPriority:
+		return priorityRank(item.Priority), true
+	case SortRating:
+		rating := item.LatestRating()
+		if rating == nil {
+			return nil, false
+		}
+		return rating.Value(), true
+	case SortTitle:
+		return strings.ToLower(string(item.Title)), true
+	default:
+		return nil, false
+	}
+}
+
+func compareValues(a, b interface{}) int {
+	switch av := a.(type) {
+	case time.Time:
+		bv := b.(time.Time)
+		if av.Before(bv) {
+			return -1
+		}
+		if av.After(bv) {
+			return 1
+		}
+	case int:
+		return av - b.(int)
+	case string:
+		return strings.Compare(av, b.(string))
+	}
+	return 0
+}
+
+// priorityRank orders priorities from low to high.
+func priorityRank(priority Priority) int {
+	switch priority {
+	case PriorityHigh:
+		return 3
+	case PriorityLow:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ContainsText reports whether text appears, ignoring case, in the title,
+// author, tags, URL, notes, review or highlights of the item.
+func (i *Item) ContainsText(text string) bool {
+	text = strings.ToLower(text)
+	fields := []string{
+		string(i.Title),
+		string(i.Author),
+		i.Metadata.URL,
+		i.Metadata.Notes,
+		i.Metadata.Review,
+	}
+	for _, tag := range i.Tags {
+		fields = append(fields, string(tag))
+	}
+	for _, highlight := range i.Highlights {
+		fields = append(fields, highlight.Text, highlight.Note)
+	}
+
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), text) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,5 +1,7 @@
 package reading
 
+import "time"
+
 type Repository interface {
 	FindAll() ([]*Item, error)
 	FindByID(id ItemID) (*Item, error)
@@ -8,6 +10,69 @@ type Repository interface {
 	Save(item *Item) error
 	Update(item *Item) error
 	Delete(id ItemID) error
+	// ReplaceAll stores items as the complete collection in one write.
+	ReplaceAll(items []*Item) error
+}
+
+// Change describes the operation behind a write, such as starting an item
+// or renaming a tag, and the items it touched.
+type Change struct {
+	Operation string
+	Items     []*Item
+	// Detail stands in for the item titles when describing the change, as
+	// in "go to golang" for a tag rename.
+	Detail string
+}
+
+// Revision is an item as a commit of the vault left it. Item is nil when
+// the commit removed it.
+type Revision struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Message string
+	Item    *Item
+}
+
+// HistoryRepository is implemented by repositories that keep the past
+// versions of the items, such as the git-backed ones.
+type HistoryRepository interface {
+	// History returns the revisions that changed the item, oldest first.
+	History(id ItemID) ([]Revision, error)
+}
+
+// SnapshotRepository is implemented by read-only repositories holding the
+// items as they were at some point, such as a past commit of the vault.
+type SnapshotRepository interface {
+	// Time is the moment the items are as of.
+	Time() time.Time
+}
+
+// ChangeRecorder is implemented by repositories that record why they were
+// written to, such as the git-backed ones in their commit messages.
+type ChangeRecorder interface {
+	// WithChange returns the repository with its writes described by change.
+	WithChange(change Change) Repository
+}
+
+type SortKey string
+
+const (
+	SortAdded    SortKey = "added"
+	SortStarted  SortKey = "started"
+	SortFinished SortKey = "finished"
+	SortPriority SortKey = "priority"
+	SortRating   SortKey = "rating"
+	SortTitle    SortKey = "title"
+)
+
+func (k SortKey) IsValid() bool {
+	switch k {
+	case SortAdded, SortStarted, SortFinished, SortPriority, SortRating, SortTitle:
+		return true
+	default:
+		return false
+	}
 }
 
 type QueryOptions struct {
@@ -15,12 +80,20 @@ type QueryOptions struct {
 	Tags     []Tag
 	Priority *Priority
 	Type     *ItemType
-	Limit    int
-	Offset   int
+	// Search matches text in the title, author, tags, URL, notes and review.
+	Search string
+	// Filter, when set, must also accept the item.
+	Filter func(item *Item) bool
+	// SortBy leaves items in storage order when empty.
+	SortBy     SortKey
+	Descending bool
+	Limit      int
+	Offset     int
 }
 
 type QueryRepository interface {
 	Repository
-	Find(options QueryOptions) ([]*Item, error)
-	Count(options QueryOptions) (int, error)
+	// Find returns the page of items matching options and how many match
+	// in all, regardless of Limit and Offset.
+	Find(options QueryOptions) ([]*Item, int, error)
 }
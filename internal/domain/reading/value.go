@@ -2,20 +2,24 @@ package reading
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
 type Status string
 
 const (
-	StatusToRead  Status = "to-read"
-	StatusReading Status = "reading"
-	StatusDone    Status = "done"
+	StatusToRead    Status = "to-read"
+	StatusReading   Status = "reading"
+	StatusPaused    Status = "paused"
+	StatusDone      Status = "done"
+	StatusAbandoned Status = "abandoned"
 )
 
 func (s Status) IsValid() bool {
 	switch s {
-	case StatusToRead, StatusReading, StatusDone:
+	case StatusToRead, StatusReading, StatusPaused, StatusDone, StatusAbandoned:
 		return true
 	default:
 		return false
@@ -50,17 +54,45 @@ const (
 	TypeArticle ItemType = "article"
 	TypeVideo   ItemType = "video"
 	TypeCourse  ItemType = "course"
+	TypePaper   ItemType = "paper"
 )
 
 func (t ItemType) IsValid() bool {
 	switch t {
-	case TypeBook, TypeArticle, TypeVideo, TypeCourse:
+	case TypeBook, TypeArticle, TypeVideo, TypeCourse, TypePaper:
 		return true
 	default:
 		return false
 	}
 }
 
+// ProgressUnit is what the position of an item is counted in.
+type ProgressUnit string
+
+const (
+	UnitPages   ProgressUnit = "pages"
+	UnitMinutes ProgressUnit = "minutes"
+	UnitLessons ProgressUnit = "lessons"
+)
+
+// ProgressUnit returns the unit progress is tracked in: minutes for videos,
+// lessons for courses and pages for everything else.
+func (t ItemType) ProgressUnit() ProgressUnit {
+	switch t {
+	case TypeVideo:
+		return UnitMinutes
+	case TypeCourse:
+		return UnitLessons
+	default:
+		return UnitPages
+	}
+}
+
+// Singular returns the name of one unit, e.g. "page".
+func (u ProgressUnit) Singular() string {
+	return strings.TrimSuffix(string(u), "s")
+}
+
 type ItemID string
 
 func NewItemID(value string) (ItemID, error) {
@@ -93,12 +125,46 @@ func (a Author) String() string {
 	return string(a)
 }
 
+// Tag labels an item. Nested tags separate their levels with "/", as in
+// programming/go, and a tag includes the tags nested under it.
 type Tag string
 
+// NewTag checks a tag name, dropping the "#" it is written with in
+// Markdown.
+func NewTag(value string) (Tag, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "#")
+	if value == "" {
+		return "", errors.New("tag cannot be empty")
+	}
+	if strings.ContainsAny(value, " \t#,") {
+		return "", fmt.Errorf("invalid tag %q: tags cannot contain spaces, commas or #", value)
+	}
+	for _, level := range strings.Split(value, "/") {
+		if level == "" {
+			return "", fmt.Errorf("invalid tag %q: empty level", value)
+		}
+	}
+	return Tag(value), nil
+}
+
 func (t Tag) String() string {
 	return string(t)
 }
 
+// Includes reports whether tag is t or is nested under it.
+func (t Tag) Includes(tag Tag) bool {
+	return tag == t || strings.HasPrefix(string(tag), string(t)+"/")
+}
+
+// Parent returns the tag t is nested under, "" for a top-level tag.
+func (t Tag) Parent() Tag {
+	i := strings.LastIndex(string(t), "/")
+	if i < 0 {
+		return ""
+	}
+	return t[:i]
+}
+
 type Rating int
 
 func NewRating(value int) (Rating, error) {
@@ -112,6 +178,9 @@ func (r Rating) Value() int {
 	return int(r)
 }
 
+// Progress tracks how far an item is. CurrentPage and TotalPages are
+// counted in the progress unit of the item, so they hold minutes for
+// videos and lessons for courses.
 type Progress struct {
 	Percentage  int
 	CurrentPage int
@@ -125,11 +194,114 @@ func NewProgress(percentage int) (*Progress, error) {
 	return &Progress{Percentage: percentage}, nil
 }
 
-type Metadata struct {
-	Added    time.Time
+// MoveTo sets the current position and, when the total is known, derives
+// the percentage from it.
+func (p *Progress) MoveTo(position int) error {
+	if position < 0 {
+		return errors.New("position cannot be negative")
+	}
+	if p.TotalPages > 0 && position > p.TotalPages {
+		return &PositionError{Position: position, Total: p.TotalPages}
+	}
+
+	p.CurrentPage = position
+	if p.TotalPages > 0 {
+		p.Percentage = position * 100 / p.TotalPages
+	}
+	return nil
+}
+
+// SetPercentage sets the percentage and, when the total is known, derives
+// the current position from it.
+func (p *Progress) SetPercentage(percentage int) error {
+	if percentage < 0 || percentage > 100 {
+		return errors.New("progress percentage must be between 0 and 100")
+	}
+
+	p.Percentage = percentage
+	if p.TotalPages > 0 {
+		p.CurrentPage = percentage * p.TotalPages / 100
+	}
+	return nil
+}
+
+// PositionError reports a position past the end of the item.
+type PositionError struct {
+	Position int
+	Total    int
+}
+
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("position %d is past the total of %d", e.Position, e.Total)
+}
+
+// ReadThrough is one completed read of an item with its own dates, rating
+// and review.
+type ReadThrough struct {
 	Started  *time.Time
 	Finished *time.Time
-	URL      string
-	Notes    string
+	Rating   *Rating
 	Review   string
 }
+
+type Metadata struct {
+	Added     time.Time
+	Started   *time.Time
+	Finished  *time.Time
+	Paused    *time.Time
+	Abandoned *time.Time
+	URL       string
+	ISBN      string
+	Notes     string
+	Review    string
+	Reason    string // why the item was abandoned
+
+	// DOI, Venue, Year and Authors describe papers for citations. Authors
+	// are written "Family, Given"; Author holds the name shown in lists.
+	DOI     string
+	Venue   string
+	Year    int
+	Authors []string
+}
+
+// Property is a key/value pair attached to an item that gitlife does not
+// interpret itself. Items holds the entries of a nested list, if any.
+type Property struct {
+	Key   string
+	Value string
+	Items []string
+}
+
+// Session is one reading session: the progress reached on a day and, when
+// known, the pages covered and how long it took.
+type Session struct {
+	Date       time.Time
+	FromPage   int
+	ToPage     int
+	Percentage int
+	Minutes    int
+}
+
+// Pages returns the number of pages read in the session.
+func (s Session) Pages() int {
+	if s.ToPage > s.FromPage {
+		return s.ToPage - s.FromPage
+	}
+	return 0
+}
+
+// Highlight is a passage saved from an item, with the page (minute or
+// lesson, after the unit of the item) it was found at and an optional note.
+type Highlight struct {
+	Added time.Time
+	Text  string
+	Page  int
+	Note  string
+}
+
+// Extra keeps the parts of an item written by hand (unknown properties and
+// free text), so they survive when the item is written back.
+type Extra struct {
+	Properties []Property
+	Body       string
+}
@@ -0,0 +1,415 @@
+package exchange
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/wguilherme/gitlife/internal/application/reading"
+	"golang.org/x/text/unicode/norm"
+)
+
+// bibtexEntry is one @type{key, field = value, ...} entry. fields holds
+// the values as plain text and raw as written, braces included.
+type bibtexEntry struct {
+	kind   string
+	key    string
+	fields map[string]string
+	raw    map[string]string
+}
+
+// bibtexMonths are the @string macros every BibTeX style defines.
+var bibtexMonths = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+// bibtexVenues are the fields naming where an entry was published, in the
+// order they are looked up.
+var bibtexVenues = []string{"journal", "booktitle", "institution", "school", "publisher", "howpublished"}
+
+// ParseBibTeX reads the entries of a .bib file. Books keep the book type,
+// every other entry becomes a paper.
+func ParseBibTeX(r io.Reader) ([]reading.ImportRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read BibTeX: %w", err)
+	}
+
+	entries, err := parseBibTeXEntries(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	records := []reading.ImportRecord{}
+	for _, entry := range entries {
+		title := entry.fields["title"]
+		if title == "" {
+			continue
+		}
+
+		record := reading.ImportRecord{
+			Title: title,
+			Type:  "paper",
+			DOI:   entry.fields["doi"],
+			URL:   entry.fields["url"],
+			ISBN:  entry.fields["isbn"],
+		}
+		if entry.kind == "book" {
+			record.Type = "book"
+		}
+		for _, field := range bibtexVenues {
+			if venue := entry.fields[field]; venue != "" {
+				record.Venue = venue
+				break
+			}
+		}
+
+		year := entry.fields["year"]
+		if year == "" && len(entry.fields["date"]) >= 4 {
+			year = entry.fields["date"][:4]
+		}
+		record.Year, _ = strconv.Atoi(year)
+
+		for _, author := range splitBibTeXAuthors(entry.raw["author"]) {
+			// A name in braces, like {World Health Organization}, is a
+			// corporate author and is not split into family and given names
+			if name := strings.TrimSpace(author); strings.HasPrefix(name, "{") && strings.HasSuffix(name, "}") {
+				record.Authors = append(record.Authors, latexToText(name))
+				continue
+			}
+			record.Authors = append(record.Authors, familyGiven(latexToText(author)))
+		}
+		record.Author = displayAuthor(record.Authors)
+
+		for _, keyword := range strings.FieldsFunc(entry.fields["keywords"], func(r rune) bool {
+			return r == ',' || r == ';'
+		}) {
+			if tag := strings.Join(strings.Fields(keyword), "-"); tag != "" {
+				record.Tags = append(record.Tags, strings.ToLower(tag))
+			}
+		}
+
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func parseBibTeXEntries(data string) ([]bibtexEntry, error) {
+	macros := make(map[string]string)
+	for i, month := range bibtexMonths {
+		macros[month] = strconv.Itoa(i + 1)
+	}
+
+	entries := []bibtexEntry{}
+	for {
+		at := strings.IndexByte(data, '@')
+		if at < 0 {
+			return entries, nil
+		}
+		data = data[at+1:]
+
+		open := strings.IndexAny(data, "{(")
+		if open < 0 {
+			return entries, nil
+		}
+		kind := strings.ToLower(strings.TrimSpace(data[:open]))
+		body, rest, err := bibtexGroup(data[open:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse @%s entry: %w", kind, err)
+		}
+		data = rest
+
+		switch kind {
+		case "comment", "preamble":
+			continue
+		case "string":
+			// Later definitions and entries can use the macro
+			definitions, err := parseBibTeXFields(body, macros)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse @string: %w", err)
+			}
+			for name, value := range definitions {
+				macros[name] = value
+			}
+			continue
+		}
+
+		key, fields, _ := strings.Cut(body, ",")
+		entry := bibtexEntry{kind: kind, key: strings.TrimSpace(key), fields: make(map[string]string)}
+		entry.raw, err = parseBibTeXFields(fields, macros)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse entry %s: %w", entry.key, err)
+		}
+		for name, value := range entry.raw {
+			entry.fields[name] = latexToText(value)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// bibtexGroup returns the content of the braced (or parenthesized) group
+// data starts with and what follows it.
+func bibtexGroup(data string) (string, string, error) {
+	depth := 0
+	for i := 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 && data[0] == '{' {
+				return data[1:i], data[i+1:], nil
+			}
+			depth--
+		case ')':
+			if depth == 0 && data[0] == '(' {
+				return data[1:i], data[i+1:], nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("unbalanced braces")
+}
+
+// parseBibTeXFields reads the name = value pairs of an entry, joining the
+// parts of a value concatenated with # and expanding the @string macros.
+// Values are returned as written, braces included; the outer delimiters
+// are dropped.
+func parseBibTeXFields(data string, macros map[string]string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for {
+		data = strings.TrimLeft(data, " \t\r\n,")
+		if data == "" {
+			return fields, nil
+		}
+
+		eq := strings.IndexByte(data, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("missing = after %q", strings.TrimSpace(data))
+		}
+		name := strings.ToLower(strings.TrimSpace(data[:eq]))
+		data = strings.TrimLeft(data[eq+1:], " \t\r\n")
+
+		value := ""
+		for {
+			var part string
+			switch {
+			case strings.HasPrefix(data, "{"):
+				group, rest, err := bibtexGroup(data)
+				if err != nil {
+					return nil, fmt.Errorf("field %s: %w", name, err)
+				}
+				part, data = group, rest
+			case strings.HasPrefix(data, `"`):
+				end := bibtexQuoteEnd(data)
+				if end < 0 {
+					return nil, fmt.Errorf("field %s: unterminated string", name)
+				}
+				part, data = data[1:end], data[end+1:]
+			default:
+				end := strings.IndexAny(data, ",#}\n")
+				if end < 0 {
+					end = len(data)
+				}
+				part, data = strings.TrimSpace(data[:end]), data[end:]
+				if _, err := strconv.Atoi(part); err != nil && part != "" {
+					macro, ok := macros[strings.ToLower(part)]
+					if !ok {
+						return nil, fmt.Errorf("field %s: undefined @string macro %q", name, part)
+					}
+					part = macro
+				}
+			}
+			value += part
+
+			data = strings.TrimLeft(data, " \t\r\n")
+			if !strings.HasPrefix(data, "#") {
+				break
+			}
+			data = strings.TrimLeft(data[1:], " \t\r\n")
+		}
+
+		fields[name] = value
+	}
+}
+
+// bibtexQuoteEnd returns the index of the quote closing the string data
+// starts with; quotes inside braces, as in {\"o}, do not count.
+func bibtexQuoteEnd(data string) int {
+	depth := 0
+	for i := 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '"':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var (
+	latexAccent  = regexp.MustCompile(`\\([` + "`" + `'^"~=.])\s*\{?([A-Za-z])\}?`)
+	latexCommand = regexp.MustCompile(`\\[a-zA-Z]+\s*`)
+	bibtexAnd    = regexp.MustCompile(`\s+and\s+`)
+	latexMarks   = map[string]rune{"`": '\u0300', "'": '\u0301', "^": '\u0302', "~": '\u0303', "=": '\u0304', ".": '\u0307', `"`: '\u0308'}
+)
+
+// latexToText turns a BibTeX value into plain text: accents become
+// characters, escapes and braces are dropped and spacing is collapsed.
+func latexToText(value string) string {
+	value = latexAccent.ReplaceAllStringFunc(value, func(match string) string {
+		parts := latexAccent.FindStringSubmatch(match)
+		return parts[2] + string(latexMarks[parts[1]])
+	})
+	value = strings.NewReplacer(`\&`, "&", `\%`, "%", `\$`, "$", `\_`, "_", `\#`, "#", "---", "—", "--", "–").Replace(value)
+	value = latexCommand.ReplaceAllString(value, "")
+	value = strings.NewReplacer("{", "", "}", "", "~", " ").Replace(value)
+	return norm.NFC.String(strings.Join(strings.Fields(value), " "))
+}
+
+// splitBibTeXAuthors splits an author field, as written, on the "and"s
+// outside braces.
+func splitBibTeXAuthors(value string) []string {
+	authors := []string{}
+	add := func(author string) {
+		if author = strings.TrimSpace(author); author != "" {
+			authors = append(authors, author)
+		}
+	}
+
+	depth, start := 0, 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+		default:
+			if depth > 0 {
+				continue
+			}
+			if match := bibtexAnd.FindStringIndex(value[i:]); match != nil && match[0] == 0 {
+				add(value[start:i])
+				start = i + match[1]
+				i = start - 1
+			}
+		}
+	}
+	add(value[start:])
+	return authors
+}
+
+// WriteBibTeX writes items as BibTeX entries: papers as @article, books as
+// @book and everything else as @misc.
+func WriteBibTeX(w io.Writer, items []reading.ItemDTO) error {
+	keys := make(map[string]bool)
+	for i, item := range items {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, bibtexEntryFor(item, keys)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bibtexEntryFor(item reading.ItemDTO, keys map[string]bool) string {
+	kind, venueField := "misc", "howpublished"
+	switch item.Type {
+	case "paper":
+		kind, venueField = "article", "journal"
+	case "book":
+		kind, venueField = "book", "publisher"
+	}
+
+	authors := citationAuthors(item)
+	year := item.Year
+	if year == 0 && item.Finished != nil && kind == "misc" {
+		year = item.Finished.Year()
+	}
+
+	key := uniqueKey(bibtexKey(authors, year, item.Title), keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "@%s{%s,\n", kind, key)
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "  %s = {%s},\n", name, value)
+		}
+	}
+	field("title", escapeBibTeX(item.Title))
+	names := make([]string, len(authors))
+	for i, author := range authors {
+		names[i] = escapeBibTeX(author)
+		// Kept whole when read back, instead of split into family and given
+		if !strings.Contains(author, ",") && strings.Contains(author, " ") {
+			names[i] = "{" + names[i] + "}"
+		}
+	}
+	field("author", strings.Join(names, " and "))
+	field(venueField, escapeBibTeX(item.Venue))
+	if year > 0 {
+		field("year", strconv.Itoa(year))
+	}
+	field("doi", item.DOI)
+	field("isbn", item.ISBN)
+	field("url", item.URL)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// bibtexKey builds the usual citation key, e.g. vaswani2017attention.
+func bibtexKey(authors []string, year int, title string) string {
+	key := "item"
+	if len(authors) > 0 {
+		family, _, _ := strings.Cut(authors[0], ",")
+		key = asciiWord(family)
+	}
+	if year > 0 {
+		key += strconv.Itoa(year)
+	}
+	for _, word := range strings.Fields(title) {
+		if word := asciiWord(word); len(word) > 3 {
+			return key + word
+		}
+	}
+	return key
+}
+
+func uniqueKey(key string, keys map[string]bool) string {
+	unique := key
+	for suffix := 'a'; keys[unique]; suffix++ {
+		unique = key + string(suffix)
+	}
+	keys[unique] = true
+	return unique
+}
+
+// asciiWord lowercases a word and keeps its ASCII letters and digits,
+// dropping accents.
+func asciiWord(word string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(word)) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func escapeBibTeX(value string) string {
+	return strings.NewReplacer("&", `\&`, "%", `\%`, "$", `\$`, "#", `\#`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,46 @@
+package exchange
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBibTeXAuthorsAndMacros(t *testing.T) {
+	data := `@string{who = "World Health Organization"}
+@string{series = "Reports" # " on " # who}
+
+@techreport{who2020,
+  title = "Q" # series,
+  author = {{World Health Organization} and Ashish Vaswani and {Barnes {\&} Noble}},
+  institution = who,
+  month = mar,
+  year = 2020,
+}`
+
+	records, err := ParseBibTeX(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseBibTeX: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+
+	record := records[0]
+	if want := "QReports on World Health Organization"; record.Title != want {
+		t.Errorf("title = %q, want %q", record.Title, want)
+	}
+	if want := "World Health Organization"; record.Venue != want {
+		t.Errorf("venue = %q, want %q", record.Venue, want)
+	}
+	if want := []string{"World Health Organization", "Vaswani, Ashish", "Barnes & Noble"}; !reflect.DeepEqual(record.Authors, want) {
+		t.Errorf("authors = %q, want %q", record.Authors, want)
+	}
+}
+
+func TestParseBibTeXUndefinedMacro(t *testing.T) {
+	_, err := ParseBibTeX(strings.NewReader(`@article{key, title = "Q" # foo}`))
+	if err == nil || !strings.Contains(err.Error(), `undefined @string macro "foo"`) {
+		t.Errorf("err = %v, want an undefined macro error", err)
+	}
+}
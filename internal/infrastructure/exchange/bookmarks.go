@@ -0,0 +1,182 @@
+package exchange
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wguilherme/gitlife/internal/application/reading"
+	"golang.org/x/net/html"
+)
+
+// bookmarkRoots are the top-level folders browsers create; they are not
+// turned into tags.
+var bookmarkRoots = map[string]bool{
+	"bookmarks":         true,
+	"bookmarks bar":     true,
+	"bookmarks toolbar": true,
+	"bookmarks menu":    true,
+	"other bookmarks":   true,
+	"mobile bookmarks":  true,
+	"favorites bar":     true,
+	"favorites":         true,
+	"unsorted":          true,
+}
+
+// bookmarkSections are the sections of Pocket and Instapaper exports. Their
+// names set the status instead of a tag.
+var bookmarkSections = map[string]string{
+	"unread":       "to-read",
+	"read archive": "done",
+	"archive":      "done",
+}
+
+// ParseBookmarksHTML reads a bookmarks file in the Netscape format, as
+// exported by browsers, Pocket and Instapaper. Links become articles tagged
+// with their folder (nested folders as "programming/go") and with the TAGS
+// attribute. Links in an archive section are marked as done.
+func ParseBookmarksHTML(r io.Reader) ([]reading.ImportRecord, error) {
+	tokenizer := html.NewTokenizer(r)
+
+	records := []reading.ImportRecord{}
+	folders := []string{}
+	heading, inHeading, pending := "", false, false
+
+	var link *reading.ImportRecord
+	var text strings.Builder
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if tokenizer.Err() == io.EOF {
+				return records, nil
+			}
+			return nil, fmt.Errorf("failed to read bookmarks: %w", tokenizer.Err())
+
+		case html.TextToken:
+			switch {
+			case link != nil:
+				text.Write(tokenizer.Text())
+			case inHeading:
+				heading += string(tokenizer.Text())
+			}
+
+		case html.StartTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "h1", "h2", "h3":
+				heading, inHeading = "", true
+			case "dl", "ul", "ol":
+				folder := ""
+				if pending {
+					folder = strings.Join(strings.Fields(heading), " ")
+				}
+				folders = append(folders, folder)
+				pending = false
+			case "a":
+				link = bookmarkRecord(token, folders)
+				text.Reset()
+				pending = false
+			}
+
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "h1", "h2", "h3":
+				inHeading, pending = false, true
+			case "dl", "ul", "ol":
+				if len(folders) > 0 {
+					folders = folders[:len(folders)-1]
+				}
+			case "a":
+				if link != nil && link.URL != "" {
+					link.Title = strings.Join(strings.Fields(text.String()), " ")
+					if link.Title == "" {
+						link.Title = link.URL
+					}
+					records = append(records, *link)
+				}
+				link = nil
+			}
+		}
+	}
+}
+
+// bookmarkRecord reads the attributes of a link. Links that are not web
+// pages (javascript:, place: and the like) get an empty URL and are skipped.
+func bookmarkRecord(token html.Token, folders []string) *reading.ImportRecord {
+	record := &reading.ImportRecord{Type: "article", Status: "to-read"}
+
+	for _, attr := range token.Attr {
+		switch attr.Key {
+		case "href":
+			if strings.HasPrefix(attr.Val, "http://") || strings.HasPrefix(attr.Val, "https://") {
+				record.URL = attr.Val
+			}
+		case "add_date", "time_added":
+			if added := parseBookmarkDate(attr.Val); added != nil {
+				record.Added = *added
+			}
+		case "tags":
+			for _, tag := range strings.Split(attr.Val, ",") {
+				if tag := bookmarkTag(tag); tag != "" {
+					record.Tags = appendTag(record.Tags, tag)
+				}
+			}
+		}
+	}
+
+	path := []string{}
+	for _, folder := range folders {
+		name := strings.ToLower(folder)
+		if status, ok := bookmarkSections[name]; ok {
+			record.Status = status
+			continue
+		}
+		if tag := bookmarkTag(folder); tag != "" && !bookmarkRoots[name] {
+			path = append(path, tag)
+		}
+	}
+	if len(path) > 0 {
+		record.Tags = appendTag(record.Tags, strings.Join(path, "/"))
+	}
+
+	return record
+}
+
+// parseBookmarkDate reads a Unix timestamp. Browsers write seconds, but
+// some exports use milliseconds or microseconds.
+func parseBookmarkDate(value string) *time.Time {
+	stamp, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || stamp <= 0 {
+		return nil
+	}
+
+	var date time.Time
+	switch {
+	case stamp > 1e14:
+		date = time.UnixMicro(stamp)
+	case stamp > 1e11:
+		date = time.UnixMilli(stamp)
+	default:
+		date = time.Unix(stamp, 0)
+	}
+	return &date
+}
+
+// bookmarkTag turns a folder or tag name into a tag: "Machine Learning"
+// becomes "machine-learning".
+func bookmarkTag(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "-"))
+}
+
+func appendTag(tags []string, tag string) []string {
+	for _, existing := range tags {
+		if existing == tag {
+			return tags
+		}
+	}
+	return append(tags, tag)
+}
@@ -0,0 +1,181 @@
+package exchange
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wguilherme/gitlife/internal/application/reading"
+)
+
+const (
+	StyleBibTeX = "bibtex"
+	StyleAPA    = "apa"
+	StyleMLA    = "mla"
+)
+
+// Styles are the citation styles, in the order they are documented.
+var Styles = []string{StyleBibTeX, StyleAPA, StyleMLA}
+
+// Cite formats a reference to an item in one of Styles.
+func Cite(item reading.ItemDTO, style string) (string, error) {
+	switch style {
+	case StyleBibTeX:
+		return bibtexEntryFor(item, make(map[string]bool)), nil
+	case StyleAPA:
+		return citeAPA(item), nil
+	case StyleMLA:
+		return citeMLA(item), nil
+	}
+	return "", fmt.Errorf("unknown citation style %q (expected %s)", style, strings.Join(Styles, ", "))
+}
+
+// citeAPA follows APA 7: "Vaswani, A., & Shazeer, N. (2017). Title. Venue.
+// https://doi.org/...".
+func citeAPA(item reading.ItemDTO) string {
+	names := []string{}
+	for _, author := range citationAuthors(item) {
+		family, given, found := strings.Cut(author, ",")
+		if !found {
+			names = append(names, family)
+			continue
+		}
+		initials := []string{}
+		for _, name := range strings.Fields(given) {
+			initials = append(initials, string([]rune(name)[0])+".")
+		}
+		names = append(names, family+", "+strings.Join(initials, " "))
+	}
+
+	byline := ""
+	switch len(names) {
+	case 0:
+	case 1:
+		byline = names[0]
+	case 2:
+		byline = names[0] + ", & " + names[1]
+	default:
+		byline = strings.Join(names[:len(names)-1], ", ") + ", & " + names[len(names)-1]
+	}
+
+	parts := []string{}
+	if byline != "" {
+		// A corporate author ends without initials: "World Health Organization."
+		if !strings.HasSuffix(byline, ".") {
+			byline += "."
+		}
+		parts = append(parts, byline)
+	}
+
+	year := "n.d."
+	if item.Year > 0 {
+		year = strconv.Itoa(item.Year)
+	}
+	parts = append(parts, "("+year+").", sentence(item.Title))
+	if item.Venue != "" {
+		parts = append(parts, sentence(item.Venue))
+	}
+	if link := citationLink(item); link != "" {
+		parts = append(parts, link)
+	}
+	return strings.Join(parts, " ")
+}
+
+// citeMLA follows MLA 9: "Vaswani, Ashish, et al. "Title." Venue, 2017,
+// https://doi.org/...".
+func citeMLA(item reading.ItemDTO) string {
+	authors := citationAuthors(item)
+	var b strings.Builder
+	switch len(authors) {
+	case 0:
+	case 1:
+		b.WriteString(sentence(authors[0]) + " ")
+	case 2:
+		b.WriteString(authors[0] + ", and " + givenFamily(authors[1]) + ". ")
+	default:
+		b.WriteString(authors[0] + ", et al. ")
+	}
+
+	if item.Type == "book" {
+		b.WriteString(sentence(item.Title))
+	} else {
+		b.WriteString(`"` + sentence(item.Title) + `"`)
+	}
+
+	details := []string{}
+	if item.Venue != "" {
+		details = append(details, item.Venue)
+	}
+	if item.Year > 0 {
+		details = append(details, strconv.Itoa(item.Year))
+	}
+	if link := citationLink(item); link != "" {
+		details = append(details, strings.TrimPrefix(strings.TrimPrefix(link, "https://"), "http://"))
+	}
+	if len(details) > 0 {
+		b.WriteString(" " + strings.Join(details, ", ") + ".")
+	}
+	return b.String()
+}
+
+// citationAuthors returns the authors of an item as "Family, Given", falling
+// back to the displayed author when the list is empty.
+func citationAuthors(item reading.ItemDTO) []string {
+	if len(item.Authors) > 0 {
+		return item.Authors
+	}
+	if item.Author == "" || item.Author == "Unknown" {
+		return nil
+	}
+	return []string{familyGiven(item.Author)}
+}
+
+// citationLink prefers the DOI over the URL.
+func citationLink(item reading.ItemDTO) string {
+	if item.DOI != "" {
+		return "https://doi.org/" + item.DOI
+	}
+	return item.URL
+}
+
+// familyGiven writes "Ashish Vaswani" as "Vaswani, Ashish". Names already
+// written that way and single names are kept.
+func familyGiven(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if strings.Contains(name, ",") {
+		return name
+	}
+	return authorLastFirst(name)
+}
+
+// givenFamily writes "Vaswani, Ashish" as "Ashish Vaswani".
+func givenFamily(name string) string {
+	family, given, found := strings.Cut(name, ",")
+	if !found {
+		return name
+	}
+	return strings.TrimSpace(given) + " " + strings.TrimSpace(family)
+}
+
+// displayAuthor is the author shown in lists: "Ashish Vaswani",
+// "Ashish Vaswani and Noam Shazeer" or "Ashish Vaswani et al.".
+func displayAuthor(authors []string) string {
+	switch len(authors) {
+	case 0:
+		return ""
+	case 1:
+		return givenFamily(authors[0])
+	case 2:
+		return givenFamily(authors[0]) + " and " + givenFamily(authors[1])
+	default:
+		return givenFamily(authors[0]) + " et al."
+	}
+}
+
+// sentence ends text with a period unless it already ends with punctuation.
+func sentence(text string) string {
+	if text == "" || strings.ContainsAny(text[len(text)-1:], ".?!") {
+		return text
+	}
+	return text + "."
+}
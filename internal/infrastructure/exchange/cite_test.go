@@ -0,0 +1,43 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/wguilherme/gitlife/internal/application/reading"
+)
+
+func TestCiteAPA(t *testing.T) {
+	tests := []struct {
+		name string
+		item reading.ItemDTO
+		want string
+	}{
+		{
+			name: "people",
+			item: reading.ItemDTO{Title: "Attention is all you need", Authors: []string{"Vaswani, Ashish", "Shazeer, Noam"}, Year: 2017},
+			want: "Vaswani, A., & Shazeer, N. (2017). Attention is all you need.",
+		},
+		{
+			name: "corporate author",
+			item: reading.ItemDTO{Title: "World health statistics", Authors: []string{"World Health Organization"}, Year: 2020},
+			want: "World Health Organization. (2020). World health statistics.",
+		},
+		{
+			name: "corporate author last",
+			item: reading.ItemDTO{Title: "Report", Authors: []string{"Vaswani, Ashish", "Google Inc."}},
+			want: "Vaswani, A., & Google Inc. (n.d.). Report.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Cite(tt.item, StyleAPA)
+			if err != nil {
+				t.Fatalf("Cite: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Cite = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wguilherme/gitlife/internal/application/reading"
+)
+
+// cslItem is the part of a CSL-JSON item (as exported by Zotero) that is
+// imported.
+type cslItem struct {
+	Type           string      `json:"type"`
+	Title          string      `json:"title"`
+	Author         []cslName   `json:"author"`
+	ContainerTitle string      `json:"container-title"`
+	Publisher      string      `json:"publisher"`
+	Issued         cslDate     `json:"issued"`
+	DOI            string      `json:"DOI"`
+	URL            string      `json:"URL"`
+	ISBN           string      `json:"ISBN"`
+	Keyword        string      `json:"keyword"`
+	NumberOfPages  json.Number `json:"number-of-pages"`
+}
+
+type cslName struct {
+	Family  string `json:"family"`
+	Given   string `json:"given"`
+	Literal string `json:"literal"`
+}
+
+type cslDate struct {
+	DateParts [][]json.Number `json:"date-parts"`
+}
+
+// cslArticles are the CSL types imported as articles; books stay books and
+// every other type becomes a paper.
+var cslArticles = map[string]bool{
+	"webpage":           true,
+	"post":              true,
+	"post-weblog":       true,
+	"article-magazine":  true,
+	"article-newspaper": true,
+}
+
+// ParseCSLJSON reads a CSL-JSON array.
+func ParseCSLJSON(r io.Reader) ([]reading.ImportRecord, error) {
+	items := []cslItem{}
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("failed to parse CSL-JSON: %w", err)
+	}
+
+	records := []reading.ImportRecord{}
+	for _, item := range items {
+		if strings.TrimSpace(item.Title) == "" {
+			continue
+		}
+
+		record := reading.ImportRecord{
+			Title: item.Title,
+			Type:  "paper",
+			Venue: item.ContainerTitle,
+			DOI:   item.DOI,
+			URL:   item.URL,
+			ISBN:  item.ISBN,
+		}
+		switch {
+		case item.Type == "book":
+			record.Type = "book"
+			record.Venue = item.Publisher
+		case cslArticles[item.Type]:
+			record.Type = "article"
+		}
+
+		if len(item.Issued.DateParts) > 0 && len(item.Issued.DateParts[0]) > 0 {
+			year, _ := item.Issued.DateParts[0][0].Int64()
+			record.Year = int(year)
+		}
+		pages, _ := item.NumberOfPages.Int64()
+		record.TotalPages = int(pages)
+
+		for _, name := range item.Author {
+			switch {
+			case name.Literal != "":
+				record.Authors = append(record.Authors, name.Literal)
+			case name.Given != "":
+				record.Authors = append(record.Authors, name.Family+", "+name.Given)
+			case name.Family != "":
+				record.Authors = append(record.Authors, name.Family)
+			}
+		}
+		record.Author = displayAuthor(record.Authors)
+
+		for _, keyword := range strings.Split(item.Keyword, ",") {
+			if tag := strings.Join(strings.Fields(keyword), "-"); tag != "" {
+				record.Tags = append(record.Tags, strings.ToLower(tag))
+			}
+		}
+
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,185 @@
+package exchange
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wguilherme/gitlife/internal/application/reading"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatJSON      = "json"
+	FormatNDJSON    = "ndjson"
+	FormatCSV       = "csv"
+	FormatYAML      = "yaml"
+	FormatGoodreads = "goodreads"
+	FormatBibTeX    = "bibtex"
+)
+
+// Formats are the export formats, in the order they are documented.
+var Formats = []string{FormatJSON, FormatNDJSON, FormatCSV, FormatYAML, FormatGoodreads, FormatBibTeX}
+
+// csvColumns is the fixed column order of the CSV export.
+var csvColumns = []string{
+	"id", "title", "author", "type", "status", "priority", "tags",
+	"progress", "unit", "current_page", "total_pages", "rating", "reads", "highlights",
+	"url", "isbn", "notes", "review",
+	"added", "started", "finished", "paused", "abandoned", "reason",
+}
+
+// ContentType returns the media type of an export format.
+func ContentType(format string) string {
+	switch format {
+	case FormatJSON:
+		return "application/json"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatYAML:
+		return "application/yaml"
+	case FormatBibTeX:
+		return "application/x-bibtex; charset=utf-8"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Export writes items in one of Formats. JSON, NDJSON and YAML hold the
+// items in full; the other formats only their listing fields.
+func Export(w io.Writer, format string, items []reading.ExportItemDTO) error {
+	listed := make([]reading.ItemDTO, len(items))
+	for i, item := range items {
+		listed[i] = item.ItemDTO
+	}
+
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(items)
+	case FormatNDJSON:
+		encoder := json.NewEncoder(w)
+		for _, item := range items {
+			if err := encoder.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatCSV:
+		return writeCSV(w, listed)
+	case FormatYAML:
+		return writeYAML(w, items)
+	case FormatGoodreads:
+		return WriteGoodreadsCSV(w, listed)
+	case FormatBibTeX:
+		return WriteBibTeX(w, listed)
+	}
+	return fmt.Errorf("unknown export format %q (expected %s)", format, strings.Join(Formats, ", "))
+}
+
+func writeCSV(w io.Writer, items []reading.ItemDTO) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+
+	date := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	}
+	number := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+
+	for _, item := range items {
+		row := []string{
+			item.ID, item.Title, item.Author, item.Type, item.Status, item.Priority,
+			strings.Join(item.Tags, " "),
+			number(item.Progress), item.Unit, number(item.CurrentPage), number(item.TotalPages),
+			number(item.Rating), number(item.Reads), number(item.Highlights),
+			item.URL, item.ISBN, item.Notes, item.Review,
+			item.Added.Format("2006-01-02"), date(item.Started), date(item.Finished),
+			date(item.Paused), date(item.Abandoned), item.Reason,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeYAML writes the JSON form of the items as YAML, so both exports
+// share the field names and their order.
+func writeYAML(w io.Writer, items []reading.ExportItemDTO) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// blockStyle drops the flow style and quotes of the JSON form; the encoder
+// quotes the strings that need it.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// ParseJSON reads items written by the JSON export: either an array or
+// one item per line (NDJSON).
+func ParseJSON(r io.Reader) ([]reading.ImportRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("\ufeff"))
+
+	items := []reading.ExportItemDTO{}
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON export: %w", err)
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		for line := 1; decoder.More(); line++ {
+			var item reading.ExportItemDTO
+			if err := decoder.Decode(&item); err != nil {
+				return nil, fmt.Errorf("failed to parse NDJSON export at item %d: %w", line, err)
+			}
+			items = append(items, item)
+		}
+	}
+
+	records := []reading.ImportRecord{}
+	for _, item := range items {
+		records = append(records, reading.ImportRecordFromDTO(item))
+	}
+	return records, nil
+}
@@ -0,0 +1,151 @@
+package exchange
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wguilherme/gitlife/internal/application/reading"
+	domain "github.com/wguilherme/gitlife/internal/domain/reading"
+)
+
+// memoryRepository keeps the items in memory, in insertion order.
+type memoryRepository struct {
+	items []*domain.Item
+}
+
+func (r *memoryRepository) FindAll() ([]*domain.Item, error) {
+	return r.items, nil
+}
+
+func (r *memoryRepository) FindByID(id domain.ItemID) (*domain.Item, error) {
+	for _, item := range r.items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return nil, fmt.Errorf("item with ID %s not found", id)
+}
+
+func (r *memoryRepository) FindByStatus(status domain.Status) ([]*domain.Item, error) {
+	items, _ := domain.Query(r.items, domain.QueryOptions{Status: &status})
+	return items, nil
+}
+
+func (r *memoryRepository) FindByTag(tag domain.Tag) ([]*domain.Item, error) {
+	items, _ := domain.Query(r.items, domain.QueryOptions{Tags: []domain.Tag{tag}})
+	return items, nil
+}
+
+func (r *memoryRepository) Save(item *domain.Item) error {
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *memoryRepository) Update(item *domain.Item) error {
+	return nil
+}
+
+func (r *memoryRepository) Delete(id domain.ItemID) error {
+	return nil
+}
+
+func (r *memoryRepository) ReplaceAll(items []*domain.Item) error {
+	r.items = items
+	return nil
+}
+
+func date(day int) time.Time {
+	return time.Date(2025, time.March, day, 9, 30, 0, 0, time.UTC)
+}
+
+// exportedVault is a vault with an item of each kind the exports have to
+// carry over: sessions, highlights, a rated read and a read again item.
+func exportedVault(t *testing.T) *memoryRepository {
+	t.Helper()
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	newItem := func(title string, itemType domain.ItemType) *domain.Item {
+		t.Helper()
+		name, err := domain.NewTitle(title)
+		must(err)
+		item, err := domain.NewItem(name, "Frank Herbert", itemType)
+		must(err)
+		item.Metadata.Added = date(1)
+		return item
+	}
+
+	dune := newItem("Dune", domain.TypeBook)
+	dune.AddTag("scifi/classic")
+	must(dune.SetTotalPages(400))
+	must(dune.Start(date(2)))
+	progress := *dune.Progress
+	must(progress.MoveTo(120))
+	must(dune.LogSession(date(3), &progress, 45))
+	_, err := dune.AddHighlight(date(3), "Fear is the mind-killer.", 110, "the litany")
+	must(err)
+	score, err := domain.NewRating(5)
+	must(err)
+	must(dune.Finish(date(9), score))
+	dune.Metadata.Review = "Still the best."
+	must(dune.Reread(date(10)))
+
+	messiah := newItem("Dune Messiah", domain.TypeBook)
+	must(messiah.Start(date(4)))
+	score, err = domain.NewRating(3)
+	must(err)
+	must(messiah.Finish(date(8), score))
+	messiah.Metadata.Review = "Darker."
+
+	talk := newItem("A talk", domain.TypeVideo)
+	must(talk.Abandon(date(5), "too long"))
+
+	return &memoryRepository{items: []*domain.Item{dune, messiah, talk}}
+}
+
+func export(t *testing.T, repo *memoryRepository, format string) []byte {
+	t.Helper()
+
+	items, err := reading.NewService(repo).Export(reading.ListQuery{})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	var out bytes.Buffer
+	if err := Export(&out, format, items); err != nil {
+		t.Fatalf("Export %s: %v", format, err)
+	}
+	return out.Bytes()
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			first := export(t, exportedVault(t), format)
+			for _, want := range []string{"Fear is the mind-killer.", "Still the best.", `"minutes":45`, "too long"} {
+				if !bytes.Contains(bytes.ReplaceAll(first, []byte(`": `), []byte(`":`)), []byte(want)) {
+					t.Fatalf("export is missing %q\n%s", want, first)
+				}
+			}
+
+			records, err := ParseJSON(bytes.NewReader(first))
+			if err != nil {
+				t.Fatalf("ParseJSON: %v", err)
+			}
+			imported := &memoryRepository{}
+			if _, err := reading.NewService(imported).Import(reading.ImportCommand{Records: records}); err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+
+			second := export(t, imported, format)
+			if !bytes.Equal(first, second) {
+				t.Errorf("export after import differs\n--- first\n%s\n--- second\n%s", first, second)
+			}
+		})
+	}
+}
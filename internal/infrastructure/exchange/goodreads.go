@@ -0,0 +1,204 @@
+package exchange
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wguilherme/gitlife/internal/application/reading"
+)
+
+// goodreadsColumns is the header of a Goodreads library export.
+var goodreadsColumns = []string{
+	"Book Id", "Title", "Author", "Author l-f", "Additional Authors", "ISBN", "ISBN13",
+	"My Rating", "Average Rating", "Publisher", "Binding", "Number of Pages",
+	"Year Published", "Original Publication Year", "Date Read", "Date Added",
+	"Bookshelves", "Bookshelves with positions", "Exclusive Shelf", "My Review",
+	"Spoiler", "Private Notes", "Read Count", "Owned Copies",
+}
+
+const goodreadsDateLayout = "2006/01/02"
+
+// goodreadsSeries is the series suffix Goodreads adds to titles, e.g.
+// "Dune (Dune #1)".
+var goodreadsSeries = regexp.MustCompile(`\s*\([^()]*#\d+(\.\d+)?\)$`)
+
+// goodreadsShelves maps exclusive shelves to statuses. Besides the three
+// built-in shelves, the usual names of custom exclusive shelves for paused
+// and abandoned books are recognized.
+var goodreadsShelves = map[string]string{
+	"to-read":           "to-read",
+	"currently-reading": "reading",
+	"read":              "done",
+	"paused":            "paused",
+	"on-hold":           "paused",
+	"abandoned":         "abandoned",
+	"did-not-finish":    "abandoned",
+	"dnf":               "abandoned",
+}
+
+// goodreadsStatusShelves is the reverse of goodreadsShelves.
+var goodreadsStatusShelves = map[string]string{
+	"to-read":   "to-read",
+	"reading":   "currently-reading",
+	"paused":    "paused",
+	"done":      "read",
+	"abandoned": "abandoned",
+}
+
+// ParseGoodreadsCSV reads a Goodreads library export. Exclusive shelves
+// become the status, the other shelves become tags.
+func ParseGoodreadsCSV(r io.Reader) ([]reading.ImportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Goodreads export: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	if _, ok := columns["Title"]; !ok {
+		return nil, fmt.Errorf("not a Goodreads export: missing Title column")
+	}
+
+	records := []reading.ImportRecord{}
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Goodreads export at line %d: %w", line, err)
+		}
+
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		record := reading.ImportRecord{
+			Title:  goodreadsSeries.ReplaceAllString(get("Title"), ""),
+			Author: get("Author"),
+			Type:   "book",
+			Review: get("My Review"),
+			Notes:  get("Private Notes"),
+		}
+		if record.Title == "" {
+			continue
+		}
+
+		record.ISBN = goodreadsISBN(get("ISBN13"))
+		if record.ISBN == "" {
+			record.ISBN = goodreadsISBN(get("ISBN"))
+		}
+		record.Rating, _ = strconv.Atoi(get("My Rating"))
+		record.TotalPages, _ = strconv.Atoi(get("Number of Pages"))
+		if added := parseGoodreadsDate(get("Date Added")); added != nil {
+			record.Added = *added
+		}
+		record.Finished = parseGoodreadsDate(get("Date Read"))
+
+		shelf := get("Exclusive Shelf")
+		record.Status = goodreadsShelves[shelf]
+		if record.Status == "" {
+			record.Status = "to-read"
+		}
+		for _, tag := range strings.Split(get("Bookshelves"), ",") {
+			tag = strings.TrimSpace(tag)
+			if _, exclusive := goodreadsShelves[tag]; tag == "" || tag == shelf || exclusive {
+				continue
+			}
+			record.Tags = append(record.Tags, tag)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// WriteGoodreadsCSV writes items in the Goodreads library export format.
+// Paused and abandoned items go to shelves of the same name.
+func WriteGoodreadsCSV(w io.Writer, items []reading.ItemDTO) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(goodreadsColumns); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		values := make(map[string]string)
+		values["Title"] = item.Title
+		if item.Author != "Unknown" {
+			values["Author"] = item.Author
+			values["Author l-f"] = authorLastFirst(item.Author)
+		}
+		if isbn := item.ISBN; isbn != "" {
+			column := "ISBN"
+			if len(strings.ReplaceAll(isbn, "-", "")) == 13 {
+				column = "ISBN13"
+			}
+			values[column] = `="` + isbn + `"`
+		}
+		values["My Rating"] = strconv.Itoa(item.Rating)
+		if item.Unit == "pages" && item.TotalPages > 0 {
+			values["Number of Pages"] = strconv.Itoa(item.TotalPages)
+		}
+		if item.Finished != nil {
+			values["Date Read"] = item.Finished.Format(goodreadsDateLayout)
+		}
+		values["Date Added"] = item.Added.Format(goodreadsDateLayout)
+
+		shelf := goodreadsStatusShelves[item.Status]
+		values["Exclusive Shelf"] = shelf
+		values["Bookshelves"] = strings.Join(append([]string{shelf}, item.Tags...), ", ")
+		values["My Review"] = item.Review
+		values["Private Notes"] = item.Notes
+		values["Read Count"] = strconv.Itoa(item.Reads)
+
+		row := make([]string, len(goodreadsColumns))
+		for i, column := range goodreadsColumns {
+			row[i] = values[column]
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// goodreadsISBN unwraps the `="0441013597"` form used by Goodreads so that
+// spreadsheets keep the leading zeros.
+func goodreadsISBN(value string) string {
+	value = strings.TrimPrefix(value, "=")
+	return strings.Trim(value, `"`)
+}
+
+func parseGoodreadsDate(value string) *time.Time {
+	for _, layout := range []string{goodreadsDateLayout, "2006-01-02"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return &date
+		}
+	}
+	return nil
+}
+
+// authorLastFirst writes "Frank Herbert" as "Herbert, Frank".
+func authorLastFirst(author string) string {
+	names := strings.Fields(author)
+	if len(names) < 2 {
+		return author
+	}
+	return names[len(names)-1] + ", " + strings.Join(names[:len(names)-1], " ")
+}
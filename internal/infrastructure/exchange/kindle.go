@@ -0,0 +1,216 @@
+package exchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wguilherme/gitlife/internal/application/reading"
+)
+
+// kindleSeparator ends every clipping in "My Clippings.txt".
+const kindleSeparator = "=========="
+
+var (
+	kindleKind     = regexp.MustCompile(`(?i)^-\s*(?:your\s+)?(highlight|note|bookmark)\b`)
+	kindlePage     = regexp.MustCompile(`(?i)\bpage\s+(\d+)`)
+	kindleLocation = regexp.MustCompile(`(?i)\b(?:location|loc\.)\s+(\d+)(?:-(\d+))?`)
+	kindleAdded    = regexp.MustCompile(`(?i)added on\s+(.+)$`)
+)
+
+// kindleDateLayouts are the date formats written by Kindle devices in
+// English; other languages leave the date unknown.
+var kindleDateLayouts = []string{
+	"Monday, January 2, 2006 3:04:05 PM",
+	"Monday, 2 January 2006 15:04:05",
+	"Monday, January 2, 2006, 3:04 PM",
+}
+
+type kindleClipping struct {
+	title    string
+	author   string
+	kind     string
+	page     int
+	location int // last location covered, 0 when unknown
+	added    time.Time
+	text     string
+}
+
+// ParseKindleClippings reads a Kindle "My Clippings.txt" file into one
+// record per book. Notes are attached to the highlight they were written
+// on; bookmarks and notes without a highlight are counted in skipped.
+func ParseKindleClippings(r io.Reader) (records []reading.ImportRecord, skipped int, err error) {
+	clippings, err := readKindleClippings(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	records = []reading.ImportRecord{}
+	books := make(map[string]int)
+	type position struct {
+		record   int
+		location int
+	}
+	highlightAt := make(map[position]int)
+
+	for _, clipping := range clippings {
+		key := clipping.title + "\x00" + clipping.author
+		index, ok := books[key]
+		if !ok {
+			index = len(records)
+			books[key] = index
+			records = append(records, reading.ImportRecord{
+				Title:  clipping.title,
+				Author: clipping.author,
+				Type:   "book",
+			})
+		}
+		record := &records[index]
+
+		switch clipping.kind {
+		case "highlight":
+			if clipping.text == "" {
+				skipped++
+				continue
+			}
+			record.Highlights = append(record.Highlights, reading.ImportHighlight{
+				Added: clipping.added,
+				Text:  clipping.text,
+				Page:  clipping.page,
+			})
+			if clipping.location > 0 {
+				highlightAt[position{index, clipping.location}] = len(record.Highlights) - 1
+			}
+		case "note":
+			at, ok := highlightAt[position{index, clipping.location}]
+			if !ok || clipping.location == 0 || clipping.text == "" {
+				skipped++
+				continue
+			}
+			highlight := &record.Highlights[at]
+			if highlight.Note != "" {
+				highlight.Note += " "
+			}
+			highlight.Note += clipping.text
+		default:
+			skipped++
+		}
+	}
+
+	return records, skipped, nil
+}
+
+func readKindleClippings(r io.Reader) ([]kindleClipping, error) {
+	clippings := []kindleClipping{}
+	lines := []string{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		line = strings.TrimPrefix(line, "\ufeff")
+		if strings.TrimSpace(line) != kindleSeparator {
+			lines = append(lines, line)
+			continue
+		}
+
+		if clipping, ok := parseKindleClipping(lines); ok {
+			clippings = append(clippings, clipping)
+		}
+		lines = lines[:0]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read clippings: %w", err)
+	}
+
+	return clippings, nil
+}
+
+// parseKindleClipping reads one clipping:
+//
+//	Dune (Frank Herbert)
+//	- Your Highlight on page 8 | Location 120-121 | Added on Friday, March 14, 2025 3:04:05 PM
+//
+//	I must not fear.
+func parseKindleClipping(lines []string) (kindleClipping, bool) {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) < 2 {
+		return kindleClipping{}, false
+	}
+
+	clipping := kindleClipping{}
+	clipping.title, clipping.author = splitKindleTitle(strings.TrimSpace(lines[0]))
+	if clipping.title == "" {
+		return clipping, false
+	}
+
+	meta := strings.TrimSpace(lines[1])
+	kind := kindleKind.FindStringSubmatch(meta)
+	if kind == nil {
+		return clipping, false
+	}
+	clipping.kind = strings.ToLower(kind[1])
+
+	if match := kindlePage.FindStringSubmatch(meta); match != nil {
+		clipping.page, _ = strconv.Atoi(match[1])
+	}
+	if match := kindleLocation.FindStringSubmatch(meta); match != nil {
+		clipping.location = kindleLocationEnd(match[1], match[2])
+	}
+	if match := kindleAdded.FindStringSubmatch(meta); match != nil {
+		for _, layout := range kindleDateLayouts {
+			if added, err := time.Parse(layout, strings.TrimSpace(match[1])); err == nil {
+				clipping.added = added
+				break
+			}
+		}
+	}
+
+	clipping.text = strings.Join(strings.Fields(strings.Join(lines[2:], " ")), " ")
+	return clipping, true
+}
+
+// splitKindleTitle separates "Title (Last, First)" into the title and the
+// author written as "First Last". Several authors are separated by ";".
+func splitKindleTitle(line string) (string, string) {
+	if !strings.HasSuffix(line, ")") {
+		return line, ""
+	}
+	open := strings.LastIndex(line, "(")
+	if open <= 0 {
+		return line, ""
+	}
+
+	title := strings.TrimSpace(line[:open])
+	authors := []string{}
+	for _, author := range strings.Split(line[open+1:len(line)-1], ";") {
+		author = strings.TrimSpace(author)
+		if last, first, found := strings.Cut(author, ","); found && !strings.Contains(first, ",") {
+			author = strings.TrimSpace(first) + " " + strings.TrimSpace(last)
+		}
+		if author != "" {
+			authors = append(authors, author)
+		}
+	}
+	return title, strings.Join(authors, ", ")
+}
+
+// kindleLocationEnd returns the last location of a range. Old devices
+// abbreviate the end, e.g. "1510-14" for 1510-1514.
+func kindleLocationEnd(start, end string) int {
+	if end == "" {
+		location, _ := strconv.Atoi(start)
+		return location
+	}
+	if len(end) < len(start) {
+		end = start[:len(start)-len(end)] + end
+	}
+	location, _ := strconv.Atoi(end)
+	return location
+}
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Commit is a commit of the vault with the files it changed.
+type Commit struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Subject string
+	Files   []string
+}
+
+// commitFormat is the pretty format parsed by parseCommit.
+const commitFormat = "%H%x1f%an%x1f%aI%x1f%s"
+
+// Log returns the commits that changed path, oldest first. Merges are seen
+// from their first parent, so a merge is one commit with everything it
+// brought in and the history reads like that of the local branch.
+func (s *Service) Log(path string) ([]Commit, error) {
+	output, err := s.runGitCommandOutput("-c", "core.quotePath=false", "log",
+		"--first-parent", "--diff-merges=first-parent", "--reverse", "--name-only",
+		"--format=%x1e"+commitFormat, "--", path)
+	if err != nil {
+		if strings.Contains(err.Error(), "does not have any commits") {
+			return []Commit{}, nil
+		}
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	commits := []Commit{}
+	for _, record := range strings.Split(output, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		if strings.Count(lines[0], "\x1f") != 3 {
+			continue
+		}
+
+		commit, err := parseCommit(lines[0])
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range lines[1:] {
+			if file = strings.TrimSpace(file); file != "" {
+				commit.Files = append(commit.Files, file)
+			}
+		}
+		commits = append(commits, *commit)
+	}
+	return commits, nil
+}
+
+// Revision returns the commit a revision, such as a hash, a branch or
+// HEAD~3, points to.
+func (s *Service) Revision(revision string) (*Commit, error) {
+	// A revision starting with "-" would be read by git as an option
+	if revision == "" || strings.HasPrefix(revision, "-") {
+		return nil, fmt.Errorf("invalid revision %q", revision)
+	}
+
+	hash, err := s.runGitCommandOutput("rev-parse", "--verify", "--quiet", "--end-of-options", revision+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown revision %q", revision)
+	}
+	output, err := s.runGitCommandOutput("show", "-s", "--format="+commitFormat, strings.TrimSpace(hash), "--")
+	if err != nil {
+		return nil, fmt.Errorf("git show failed: %w", err)
+	}
+	return parseCommit(strings.TrimSpace(output))
+}
+
+// RevisionAt returns the last commit of the current branch made up to t.
+func (s *Service) RevisionAt(t time.Time) (*Commit, error) {
+	output, err := s.runGitCommandOutput("log", "-1", "--first-parent",
+		"--before="+t.Format(time.RFC3339), "--format="+commitFormat, "HEAD", "--")
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+	if strings.TrimSpace(output) == "" {
+		return nil, fmt.Errorf("no commit of the vault is from %s or earlier", t.Format("2006-01-02 15:04"))
+	}
+	return parseCommit(strings.TrimSpace(output))
+}
+
+// Files returns the files under dir, relative to the vault, as of revision.
+func (s *Service) Files(revision, dir string) ([]string, error) {
+	output, err := s.runGitCommandOutput("-c", "core.quotePath=false", "ls-tree", "-r", "--name-only", revision, "--", dir)
+	if err != nil {
+		return nil, fmt.Errorf("git ls-tree failed: %w", err)
+	}
+	files := []string{}
+	for _, file := range strings.Split(output, "\n") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+func parseCommit(line string) (*Commit, error) {
+	fields := strings.Split(line, "\x1f")
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("unexpected git log output %q", line)
+	}
+
+	date, err := time.Parse(time.RFC3339, fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q in git log: %w", fields[2], err)
+	}
+	return &Commit{Hash: fields[0], Author: fields[1], Date: date, Subject: fields[3]}, nil
+}
+
+// Show returns the content of path, relative to the vault, as of revision.
+func (s *Service) Show(revision, path string) ([]byte, error) {
+	cmd := exec.Command("git", "show", revision+":"+path)
+	cmd.Dir = s.repoPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git show failed: %w", err)
+	}
+	return output, nil
+}
@@ -0,0 +1,238 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Merger resolves a conflicted file from its three versions: base is the
+// common ancestor, ours the local version and theirs the remote one. It
+// returns the merged content and the conflicts it could not resolve.
+type Merger interface {
+	Handles(path string) bool
+	Merge(path string, base, ours, theirs []byte) ([]byte, []string, error)
+}
+
+// ConflictError reports a merge that needs manual resolution. The vault is
+// left mid-merge, with the conflicting lines marked the way git does.
+type ConflictError struct {
+	Paths     []string
+	Conflicts []string
+}
+
+func (e *ConflictError) Error() string {
+	message := fmt.Sprintf("merge needs manual resolution in %s", strings.Join(e.Paths, ", "))
+	for _, conflict := range e.Conflicts {
+		message += "\n  " + conflict
+	}
+	return message + "\nresolve the conflicts in those files and sync again to finish the merge, or run git merge --abort"
+}
+
+// SetMerger sets the merger used for the files git cannot merge by itself.
+func (s *Service) SetMerger(merger Merger) {
+	s.merger = merger
+}
+
+// MergeInProgress reports whether a merge was left for manual resolution.
+func (s *Service) MergeInProgress() bool {
+	return fileExists(filepath.Join(s.repoPath, ".git", "MERGE_HEAD"))
+}
+
+// Conflicts returns the files with unresolved conflicts.
+func (s *Service) Conflicts() ([]string, error) {
+	output, err := s.runGitCommandOutput("diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %w", err)
+	}
+	return strings.Fields(output), nil
+}
+
+// finishMerge commits a merge left for manual resolution once none of its
+// files holds conflict markers any more.
+func (s *Service) finishMerge() error {
+	if !s.MergeInProgress() {
+		return nil
+	}
+
+	paths, err := s.Conflicts()
+	if err != nil {
+		return err
+	}
+	unresolved := []string{}
+	for _, path := range paths {
+		content, err := os.ReadFile(filepath.Join(s.repoPath, path))
+		if err == nil && hasConflictMarkers(content) {
+			unresolved = append(unresolved, path)
+			continue
+		}
+		if err := s.runGitCommand("add", "-A", "--", path); err != nil {
+			return fmt.Errorf("git add failed: %w", err)
+		}
+	}
+	if len(unresolved) > 0 {
+		return &ConflictError{Paths: unresolved}
+	}
+
+	if err := s.runGitCommand("commit", "--no-edit"); err != nil {
+		return fmt.Errorf("failed to finish the merge: %w", err)
+	}
+	return nil
+}
+
+// resolveConflicts merges the conflicted files the merger handles and
+// commits the merge when nothing is left to resolve by hand.
+func (s *Service) resolveConflicts(paths []string) error {
+	unresolved := &ConflictError{}
+	for _, path := range paths {
+		resolved, conflicts, err := s.mergeFile(path)
+		if err != nil {
+			return err
+		}
+		if !resolved {
+			unresolved.Paths = append(unresolved.Paths, path)
+			unresolved.Conflicts = append(unresolved.Conflicts, conflicts...)
+		}
+	}
+	if len(unresolved.Paths) > 0 {
+		return unresolved
+	}
+
+	if err := s.runGitCommand("commit", "--no-edit"); err != nil {
+		return fmt.Errorf("failed to commit the merge: %w", err)
+	}
+	return nil
+}
+
+// mergeFile merges a conflicted file with the merger and stages the
+// result. It returns false, with what is left to resolve, when the merger
+// does not handle the file, the file was deleted on a side or the merger
+// found conflicts; the file then keeps the markers written by git.
+func (s *Service) mergeFile(path string) (bool, []string, error) {
+	if s.merger == nil || !s.merger.Handles(path) {
+		return false, nil, nil
+	}
+
+	base, _ := s.stage(1, path)
+	ours, err := s.stage(2, path)
+	if err != nil {
+		return false, []string{path + ": deleted here and changed on the other side"}, nil
+	}
+	theirs, err := s.stage(3, path)
+	if err != nil {
+		return false, []string{path + ": changed here and deleted on the other side"}, nil
+	}
+
+	merged, conflicts, err := s.merger.Merge(path, base, ours, theirs)
+	if err != nil {
+		return false, []string{fmt.Sprintf("%s: %v", path, err)}, nil
+	}
+	if len(conflicts) > 0 {
+		return false, conflicts, nil
+	}
+
+	if err := os.WriteFile(filepath.Join(s.repoPath, path), merged, 0644); err != nil {
+		return false, nil, fmt.Errorf("failed to write merged %s: %w", path, err)
+	}
+	if err := s.Add([]string{path}); err != nil {
+		return false, nil, err
+	}
+	return true, nil, nil
+}
+
+// stage returns a version of a conflicted file: 1 is the common ancestor,
+// 2 the local version and 3 the remote one.
+func (s *Service) stage(n int, path string) ([]byte, error) {
+	return s.Show(fmt.Sprintf(":%d", n), path)
+}
+
+func hasConflictMarkers(content []byte) bool {
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, "<<<<<<< ") || strings.HasPrefix(line, ">>>>>>> ") {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeDriver is the name of the git merge driver for the reading files.
+// git runs it as the gitlife binary found on the PATH, whichever of the
+// CLI or the server registered it, so the config survives the binary being
+// moved or upgraded.
+const MergeDriver = "gitlife"
+
+// InstallMergeDriver registers gitlife as the merge driver of the reading
+// files: the driver goes in the local git config and the files are marked
+// with it in .gitattributes, so plain git and other clients merge them item
+// by item too. A changed .gitattributes is committed on its own, so the
+// other clones receive it on their next pull.
+func (s *Service) InstallMergeDriver() error {
+	if err := s.runGitCommand("config", "merge."+MergeDriver+".name", "gitlife reading list merge"); err != nil {
+		return fmt.Errorf("failed to set the merge driver: %w", err)
+	}
+	if err := s.runGitCommand("config", "merge."+MergeDriver+".driver", MergeDriver+" merge-driver %O %A %B %P"); err != nil {
+		return fmt.Errorf("failed to set the merge driver: %w", err)
+	}
+
+	path := filepath.Join(s.repoPath, ".gitattributes")
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read .gitattributes: %w", err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	text := string(content)
+	for _, pattern := range []string{s.folder + "/reading.md", s.folder + "/reading/*.md"} {
+		entry := fmt.Sprintf("%s merge=%s", filepath.ToSlash(pattern), MergeDriver)
+		if containsLine(lines, entry) {
+			continue
+		}
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		text += entry + "\n"
+	}
+	if text == string(content) {
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		return fmt.Errorf("failed to write .gitattributes: %w", err)
+	}
+	if err := s.runGitCommand("add", "--", ".gitattributes"); err != nil {
+		return fmt.Errorf("git add failed: %w", err)
+	}
+	if err := s.runGitCommand("commit", "-m", "Merge the reading files with the gitlife merge driver", "--", ".gitattributes"); err != nil {
+		return fmt.Errorf("failed to commit .gitattributes: %w", err)
+	}
+	return nil
+}
+
+// MergeText merges other into current line by line, the way git does,
+// leaving markers around the lines both sides changed. It reports whether
+// such conflicts were left.
+func MergeText(current, base, other string) (bool, error) {
+	cmd := exec.Command("git", "merge-file", "-L", "ours", "-L", "base", "-L", "theirs", current, base, other)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return false, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return true, nil
+	}
+	return false, fmt.Errorf("git merge-file failed: %w\nOutput: %s", err, output)
+}
+
+func containsLine(lines []string, line string) bool {
+	for _, other := range lines {
+		if strings.TrimSpace(other) == line {
+			return true
+		}
+	}
+	return false
+}
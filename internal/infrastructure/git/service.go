@@ -14,7 +14,9 @@ type Service struct {
 	repoPath   string
 	repoURL    string
 	sshKeyPath string
+	folder     string
 	userConfig UserConfig
+	merger     Merger
 	debug      bool
 }
 
@@ -28,6 +30,7 @@ func NewService(cfg *config.Config) (*Service, error) {
 		repoPath:   cfg.VaultPath,
 		repoURL:    cfg.VaultRepo,
 		sshKeyPath: cfg.SSHKeyPath,
+		folder:     cfg.GitLifeFolder,
 		userConfig: UserConfig{
 			Name:  cfg.GitUserName,
 			Email: cfg.GitUserEmail,
@@ -98,6 +101,11 @@ func (s *Service) Clone() error {
 	return s.configureUser()
 }
 
+// Pull fetches the remote and merges it into the local branch. The files
+// git cannot merge line by line are handed to the merger, if one is set;
+// when conflicts remain, the vault is left mid-merge and a *ConflictError
+// is returned. The next pull finishes that merge once the conflicts are
+// resolved.
 func (s *Service) Pull() error {
 	s.setupSSH()
 
@@ -105,10 +113,27 @@ func (s *Service) Pull() error {
 		return fmt.Errorf("repository does not exist at %s", s.repoPath)
 	}
 
-	if err := s.runGitCommand("pull", "--rebase"); err != nil {
+	if err := s.finishMerge(); err != nil {
+		return err
+	}
+
+	if err := s.runGitCommand("fetch"); err != nil {
+		return fmt.Errorf("git fetch failed: %w", err)
+	}
+
+	upstream, err := s.runGitCommandOutput("rev-parse", "--abbrev-ref", "@{upstream}")
+	if err != nil {
 		return fmt.Errorf("git pull failed: %w", err)
 	}
 
+	if err := s.runGitCommand("merge", "--no-edit", strings.TrimSpace(upstream)); err != nil {
+		paths, conflictsErr := s.Conflicts()
+		if conflictsErr != nil || len(paths) == 0 {
+			return fmt.Errorf("git pull failed: %w", err)
+		}
+		return s.resolveConflicts(paths)
+	}
+
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"errors"
 	"log"
 	"time"
 )
@@ -54,19 +55,13 @@ func (s *SyncService) Stop() {
 }
 
 func (s *SyncService) sync() error {
-	// Pull latest changes
-	if err := s.git.Pull(); err != nil {
-		log.Printf("Warning: git pull failed: %v", err)
-	}
-
-	// Check for local changes
+	// Commit local changes first, so the pull can merge them
 	hasChanges, err := s.git.HasChanges()
 	if err != nil {
 		return err
 	}
 
-	// If there are changes, commit and push
-	if hasChanges {
+	if hasChanges && !s.git.MergeInProgress() {
 		log.Println("Local changes detected, committing...")
 
 		if err := s.git.Add([]string{"."}); err != nil {
@@ -76,14 +71,28 @@ func (s *SyncService) sync() error {
 		if err := s.git.Commit("Auto-sync from GitLife"); err != nil {
 			return err
 		}
+	}
 
-		if err := s.git.Push(); err != nil {
+	// Pull latest changes; a merge left for manual resolution stops the sync
+	if err := s.git.Pull(); err != nil {
+		var conflict *ConflictError
+		if errors.As(err, &conflict) {
 			return err
 		}
+		log.Printf("Warning: git pull failed: %v", err)
+		if !hasChanges {
+			return nil
+		}
+	}
 
-		log.Println("Changes pushed successfully")
+	// Push local changes and the merge of remote ones, if any
+	if err := s.git.Push(); err != nil {
+		return err
 	}
 
+	if hasChanges {
+		log.Println("Changes pushed successfully")
+	}
 	return nil
 }
 
@@ -1,48 +1,135 @@
 package http
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/wguilherme/gitlife/internal/application/reading"
+	"github.com/wguilherme/gitlife/internal/config"
+	"github.com/wguilherme/gitlife/internal/infrastructure/exchange"
+	"github.com/wguilherme/gitlife/internal/infrastructure/git"
+	"github.com/wguilherme/gitlife/internal/infrastructure/storage"
 )
 
 type ReadingHandler struct {
 	service *reading.Service
+	config  *config.Config
 }
 
-func NewReadingHandler(service *reading.Service) *ReadingHandler {
+func NewReadingHandler(service *reading.Service, config *config.Config) *ReadingHandler {
 	return &ReadingHandler{
 		service: service,
+		config:  config,
 	}
 }
 
 // GET /api/reading
 func (h *ReadingHandler) List(c *gin.Context) {
-	status := c.Query("status")
-	tag := c.Query("tag")
+	query, err := listQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	var items []reading.ItemDTO
-	var err error
+	service, err := h.serviceAt(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := service.Query(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
 
-	switch {
-	case status != "":
-		items, err = h.service.ListByStatus(status)
-	case tag != "":
-		items, err = h.service.ListByTag(tag)
-	default:
-		items, err = h.service.ListAll()
+// serviceAt returns the service for the at query parameter, a date (2025,
+// 2025-03 or 2025-03-14) or git revision: the reading list as a past commit
+// of the vault left it, read-only, whose hash goes in the X-Gitlife-Commit
+// header. Without at it is the current reading list.
+func (h *ReadingHandler) serviceAt(c *gin.Context) (*reading.Service, error) {
+	at := c.Query("at")
+	if at == "" {
+		return h.service, nil
 	}
 
+	gitService, err := git.NewService(h.config)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, err
+	}
+	repo, err := storage.NewRevisionRepository(h.config, gitService, at)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Header("X-Gitlife-Commit", repo.Commit().Hash)
+	return reading.NewService(repo), nil
+}
+
+// GET /api/reading/export
+// Takes the filters of List and format (json, ndjson, csv, yaml, goodreads).
+func (h *ReadingHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", exchange.FormatJSON)
+
+	query, err := listQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"items": items,
-		"count": len(items),
-	})
+	items, err := h.service.Export(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var body bytes.Buffer
+	if err := exchange.Export(&body, format, items); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="reading.%s"`, exportExtension(format)))
+	c.Data(http.StatusOK, exchange.ContentType(format), body.Bytes())
+}
+
+func exportExtension(format string) string {
+	switch format {
+	case exchange.FormatGoodreads:
+		return "csv"
+	case exchange.FormatBibTeX:
+		return "bib"
+	}
+	return format
+}
+
+// listQuery reads the filters shared by List and Export.
+func listQuery(c *gin.Context) (reading.ListQuery, error) {
+	query := reading.ListQuery{
+		Expression: c.Query("q"),
+		Status:     c.Query("status"),
+		Tags:       c.QueryArray("tag"),
+		Priority:   c.Query("priority"),
+		Type:       c.Query("type"),
+		Search:     c.Query("search"),
+		Sort:       c.Query("sort"),
+	}
+
+	var err error
+	if query.Limit, err = intQuery(c, "limit"); err != nil {
+		return query, err
+	}
+	if query.Offset, err = intQuery(c, "offset"); err != nil {
+		return query, err
+	}
+	return query, nil
 }
 
 // GET /api/reading/:id
@@ -58,20 +145,182 @@ func (h *ReadingHandler) GetItem(c *gin.Context) {
 	c.JSON(http.StatusOK, item)
 }
 
+// GET /api/reading/next
+func (h *ReadingHandler) Next(c *gin.Context) {
+	weights, err := reading.ParseNextWeights(h.config.NextWeights, reading.DefaultNextWeights)
+	if err == nil {
+		weights, err = reading.ParseNextWeights(c.Query("weights"), weights)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+
+	result, err := h.service.Next(reading.NextQuery{
+		Expression: c.Query("q"),
+		Limit:      limit,
+		Weights:    weights,
+		Random:     c.Query("random") == "true",
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GET /api/reading/duplicates
+func (h *ReadingHandler) GetDuplicates(c *gin.Context) {
+	groups, err := h.service.Duplicates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// POST /api/reading/duplicates/merge
+func (h *ReadingHandler) MergeDuplicates(c *gin.Context) {
+	merged, err := h.service.MergeDuplicates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, merged)
+}
+
+// POST /api/reading/merge
+func (h *ReadingHandler) Merge(c *gin.Context) {
+	var req struct {
+		IDs []string `json:"ids" binding:"required,min=2"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.service.Merge(reading.MergeCommand{IDs: req.IDs})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// GET /api/reading/:id/cite
+func (h *ReadingHandler) Cite(c *gin.Context) {
+	item, err := h.service.GetItem(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	citation, err := exchange.Cite(*item, c.DefaultQuery("style", exchange.StyleAPA))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.String(http.StatusOK, citation)
+}
+
 // POST /api/reading
 func (h *ReadingHandler) AddItem(c *gin.Context) {
-	var cmd reading.AddItemCommand
-	if err := c.ShouldBindJSON(&cmd); err != nil {
+	var req struct {
+		Title      string   `json:"title"`
+		Author     string   `json:"author"`
+		Type       string   `json:"type"`
+		Priority   string   `json:"priority"`
+		Tags       []string `json:"tags"`
+		URL        string   `json:"url"`
+		TotalPages int      `json:"total_pages,omitempty"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.service.AddItem(cmd); err != nil {
+	cmd := reading.AddItemCommand{
+		Title:      req.Title,
+		Author:     req.Author,
+		Type:       req.Type,
+		Priority:   req.Priority,
+		Tags:       req.Tags,
+		URL:        req.URL,
+		TotalPages: req.TotalPages,
+	}
+
+	item, err := h.service.AddItem(cmd)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Item added successfully"})
+	c.JSON(http.StatusCreated, item)
+}
+
+// PUT /api/reading/:id
+func (h *ReadingHandler) UpdateItem(c *gin.Context) {
+	id := c.Param("id")
+	var req struct {
+		Title      *string   `json:"title,omitempty"`
+		Author     *string   `json:"author,omitempty"`
+		Type       *string   `json:"type,omitempty"`
+		Priority   *string   `json:"priority,omitempty"`
+		Tags       *[]string `json:"tags,omitempty"`
+		URL        *string   `json:"url,omitempty"`
+		Notes      *string   `json:"notes,omitempty"`
+		TotalPages *int      `json:"total_pages,omitempty"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd := reading.UpdateItemCommand{
+		ItemID:     id,
+		Title:      req.Title,
+		Author:     req.Author,
+		Type:       req.Type,
+		Priority:   req.Priority,
+		Tags:       req.Tags,
+		URL:        req.URL,
+		Notes:      req.Notes,
+		TotalPages: req.TotalPages,
+	}
+
+	item, err := h.service.UpdateItem(cmd)
+	if err != nil {
+		var notFound *reading.NotFoundError
+		var ambiguous *reading.AmbiguousMatchError
+		var storageErr *reading.StorageError
+		switch {
+		case errors.As(err, &notFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.As(err, &ambiguous):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "candidates": ambiguous.Candidates})
+		case errors.As(err, &storageErr):
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
 }
 
 // PUT /api/reading/:id/start
@@ -90,8 +339,10 @@ func (h *ReadingHandler) StartReading(c *gin.Context) {
 func (h *ReadingHandler) UpdateProgress(c *gin.Context) {
 	id := c.Param("id")
 	var req struct {
-		Percentage  int `json:"percentage" binding:"required,min=0,max=100"`
-		CurrentPage int `json:"current_page,omitempty"`
+		Percentage  *int   `json:"percentage,omitempty" binding:"omitempty,min=0,max=100"`
+		CurrentPage int    `json:"current_page,omitempty"`
+		Unit        string `json:"unit,omitempty"`
+		Minutes     int    `json:"minutes,omitempty" binding:"min=0"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -103,9 +354,11 @@ func (h *ReadingHandler) UpdateProgress(c *gin.Context) {
 		ItemID:      id,
 		Percentage:  req.Percentage,
 		CurrentPage: req.CurrentPage,
+		Unit:        req.Unit,
+		Minutes:     req.Minutes,
 	}
 
-	if err := h.service.UpdateProgress(cmd); err != nil {
+	if _, err := h.service.UpdateProgress(cmd); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -113,6 +366,163 @@ func (h *ReadingHandler) UpdateProgress(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Progress updated"})
 }
 
+// GET /api/reading/:id/sessions
+func (h *ReadingHandler) GetSessions(c *gin.Context) {
+	id := c.Param("id")
+
+	sessions, err := h.service.Sessions(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// GET /api/reading/:id/highlights
+// With format=markdown the highlights are returned as a Markdown document.
+func (h *ReadingHandler) GetHighlights(c *gin.Context) {
+	id := c.Param("id")
+
+	if c.Query("format") == "markdown" {
+		markdown, err := h.service.HighlightsMarkdown(id)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(markdown))
+		return
+	}
+
+	highlights, err := h.service.Highlights(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, highlights)
+}
+
+// POST /api/reading/:id/highlights
+func (h *ReadingHandler) AddHighlight(c *gin.Context) {
+	id := c.Param("id")
+	var req struct {
+		Text string `json:"text" binding:"required"`
+		Page int    `json:"page,omitempty" binding:"min=0"`
+		Note string `json:"note,omitempty"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cmd := reading.AddHighlightCommand{
+		ItemID: id,
+		Text:   req.Text,
+		Page:   req.Page,
+		Note:   req.Note,
+	}
+
+	highlight, err := h.service.AddHighlight(cmd)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, highlight)
+}
+
+// GET /api/reading/:id/reads
+func (h *ReadingHandler) GetReadThroughs(c *gin.Context) {
+	id := c.Param("id")
+
+	reads, err := h.service.ReadThroughs(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reads)
+}
+
+// GET /api/reading/:id/history
+func (h *ReadingHandler) GetHistory(c *gin.Context) {
+	id := c.Param("id")
+
+	history, err := h.service.History(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// PUT /api/reading/:id/pause
+func (h *ReadingHandler) PauseReading(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.service.PauseReading(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reading paused"})
+}
+
+// PUT /api/reading/:id/resume
+func (h *ReadingHandler) ResumeReading(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.service.ResumeReading(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reading resumed"})
+}
+
+// PUT /api/reading/:id/abandon
+func (h *ReadingHandler) AbandonReading(c *gin.Context) {
+	id := c.Param("id")
+	var req struct {
+		Reason string `json:"reason,omitempty"`
+	}
+
+	// The body is optional
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	cmd := reading.AbandonItemCommand{
+		ItemID: id,
+		Reason: req.Reason,
+	}
+
+	if err := h.service.AbandonReading(cmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reading abandoned"})
+}
+
+// PUT /api/reading/:id/reread
+func (h *ReadingHandler) RereadItem(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.service.RereadItem(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reading restarted"})
+}
+
 // PUT /api/reading/:id/finish
 func (h *ReadingHandler) FinishReading(c *gin.Context) {
 	id := c.Param("id")
@@ -154,30 +564,34 @@ func (h *ReadingHandler) DeleteItem(c *gin.Context) {
 
 // GET /api/reading/stats
 func (h *ReadingHandler) GetStats(c *gin.Context) {
-	all, err := h.service.ListAll()
+	service, err := h.serviceAt(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	stats := struct {
-		Total    int `json:"total"`
-		ToRead   int `json:"to_read"`
-		Reading  int `json:"reading"`
-		Finished int `json:"finished"`
-	}{}
-
-	for _, item := range all {
-		stats.Total++
-		switch item.Status {
-		case "to-read":
-			stats.ToRead++
-		case "reading":
-			stats.Reading++
-		case "finished":
-			stats.Finished++
-		}
+	stats, err := service.Stats(reading.StatsQuery{
+		From:    c.Query("from"),
+		To:      c.Query("to"),
+		GroupBy: c.Query("group_by"),
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	c.JSON(http.StatusOK, stats)
 }
+
+func intQuery(c *gin.Context, key string) (int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return parsed, nil
+}
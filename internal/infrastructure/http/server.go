@@ -8,7 +8,6 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/wguilherme/gitlife/internal/application/reading"
 	"github.com/wguilherme/gitlife/internal/config"
-	domainReading "github.com/wguilherme/gitlife/internal/domain/reading"
 	"github.com/wguilherme/gitlife/internal/infrastructure/git"
 	"github.com/wguilherme/gitlife/internal/infrastructure/storage"
 )
@@ -50,7 +49,8 @@ func (s *Server) SetupRoutes() error {
 	}
 
 	// Initialize handlers
-	readingHandler := NewReadingHandler(readingService)
+	readingHandler := NewReadingHandler(readingService, s.config)
+	tagHandler := NewTagHandler(readingService)
 	vaultHandler := NewVaultHandler(s.config)
 
 	// Health check
@@ -70,14 +70,39 @@ func (s *Server) SetupRoutes() error {
 		{
 			reading.GET("", readingHandler.List)
 			reading.GET("/stats", readingHandler.GetStats)
+			reading.GET("/export", readingHandler.Export)
+			reading.GET("/duplicates", readingHandler.GetDuplicates)
+			reading.GET("/next", readingHandler.Next)
 			reading.GET("/:id", readingHandler.GetItem)
+			reading.GET("/:id/sessions", readingHandler.GetSessions)
+			reading.GET("/:id/reads", readingHandler.GetReadThroughs)
+			reading.GET("/:id/history", readingHandler.GetHistory)
+			reading.GET("/:id/highlights", readingHandler.GetHighlights)
+			reading.GET("/:id/cite", readingHandler.Cite)
 			reading.POST("", readingHandler.AddItem)
+			reading.POST("/merge", readingHandler.Merge)
+			reading.POST("/duplicates/merge", readingHandler.MergeDuplicates)
+			reading.POST("/:id/highlights", readingHandler.AddHighlight)
+			reading.PUT("/:id", readingHandler.UpdateItem)
 			reading.PUT("/:id/start", readingHandler.StartReading)
 			reading.PUT("/:id/progress", readingHandler.UpdateProgress)
 			reading.PUT("/:id/finish", readingHandler.FinishReading)
+			reading.PUT("/:id/pause", readingHandler.PauseReading)
+			reading.PUT("/:id/resume", readingHandler.ResumeReading)
+			reading.PUT("/:id/abandon", readingHandler.AbandonReading)
+			reading.PUT("/:id/reread", readingHandler.RereadItem)
 			reading.DELETE("/:id", readingHandler.DeleteItem)
 		}
 
+		// Tag routes
+		tags := api.Group("/tags")
+		{
+			tags.GET("", tagHandler.List)
+			tags.POST("/rename", tagHandler.Rename)
+			tags.POST("/merge", tagHandler.Merge)
+			tags.DELETE("/*tag", tagHandler.Delete)
+		}
+
 		// Vault routes
 		vault := api.Group("/vault")
 		{
@@ -92,17 +117,17 @@ func (s *Server) SetupRoutes() error {
 }
 
 func (s *Server) initReadingService() (*reading.Service, error) {
-	var repo domainReading.Repository
+	if err := s.config.Validate(); err != nil {
+		return nil, err
+	}
 
 	// Try to initialize git service if configured
 	gitService, err := git.NewService(s.config)
-	if err == nil && gitService.RepoExists() {
-		repo = storage.NewMarkdownRepositoryWithGit(s.config, gitService)
-	} else {
-		repo = storage.NewMarkdownRepository(s.config.VaultPath)
+	if err != nil || !gitService.RepoExists() {
+		gitService = nil
 	}
 
-	return reading.NewService(repo), nil
+	return reading.NewService(storage.NewRepository(s.config, gitService)), nil
 }
 
 func (s *Server) Start() error {
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wguilherme/gitlife/internal/application/reading"
+)
+
+type TagHandler struct {
+	service *reading.Service
+}
+
+func NewTagHandler(service *reading.Service) *TagHandler {
+	return &TagHandler{
+		service: service,
+	}
+}
+
+// GET /api/tags
+func (h *TagHandler) List(c *gin.Context) {
+	tags, err := h.service.Tags()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// POST /api/tags/rename
+func (h *TagHandler) Rename(c *gin.Context) {
+	var req struct {
+		From string `json:"from" binding:"required"`
+		To   string `json:"to" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	changed, err := h.service.RenameTag(reading.RenameTagCommand{From: req.From, To: req.To})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": changed})
+}
+
+// POST /api/tags/merge
+func (h *TagHandler) Merge(c *gin.Context) {
+	var req struct {
+		From []string `json:"from" binding:"required,min=1"`
+		To   string   `json:"to" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	changed, err := h.service.MergeTags(reading.MergeTagsCommand{From: req.From, To: req.To})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": changed})
+}
+
+// DELETE /api/tags/*tag
+func (h *TagHandler) Delete(c *gin.Context) {
+	changed, err := h.service.DeleteTag(strings.TrimPrefix(c.Param("tag"), "/"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": changed})
+}
@@ -1,11 +1,13 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/wguilherme/gitlife/internal/config"
 	"github.com/wguilherme/gitlife/internal/infrastructure/git"
+	"github.com/wguilherme/gitlife/internal/infrastructure/storage"
 )
 
 type VaultHandler struct {
@@ -33,7 +35,15 @@ func (h *VaultHandler) GetStatus(c *gin.Context) {
 		"vault_repo": h.config.VaultRepo,
 	}
 
-	if exists {
+	if exists && svc.MergeInProgress() {
+		conflicts, err := svc.Conflicts()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		status["status"] = "conflicted"
+		status["conflicts"] = conflicts
+	} else if exists {
 		// Could add more detailed git status here
 		status["status"] = "ready"
 	} else {
@@ -69,6 +79,11 @@ func (h *VaultHandler) Initialize(c *gin.Context) {
 		return
 	}
 
+	if err := svc.InstallMergeDriver(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "Vault initialized successfully",
 		"vault_path": h.config.VaultPath,
@@ -100,6 +115,11 @@ func (h *VaultHandler) Clone(c *gin.Context) {
 		return
 	}
 
+	if err := svc.InstallMergeDriver(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "Vault cloned successfully",
 		"vault_path": h.config.VaultPath,
@@ -120,7 +140,17 @@ func (h *VaultHandler) Sync(c *gin.Context) {
 		return
 	}
 
+	svc.SetMerger(storage.NewMerger(h.config))
 	if err := svc.Pull(); err != nil {
+		var conflict *git.ConflictError
+		if errors.As(err, &conflict) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":     "merge needs manual resolution",
+				"paths":     conflict.Paths,
+				"conflicts": conflict.Conflicts,
+			})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
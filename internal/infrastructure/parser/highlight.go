@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+	"gopkg.in/yaml.v3"
+)
+
+// highlightNoteSeparator separates the quoted text of a highlight from the
+// note about it.
+const highlightNoteSeparator = " — "
+
+// formatHighlight writes a highlight as a list entry, e.g.
+// `2025-03-14, page 42: "The text" — my note`. The position is named after
+// the progress unit of the item.
+func formatHighlight(highlight reading.Highlight, unit reading.ProgressUnit) string {
+	entry := highlight.Added.Format("2006-01-02")
+	if highlight.Page > 0 {
+		entry += fmt.Sprintf(", %s %d", unit.Singular(), highlight.Page)
+	}
+	entry += fmt.Sprintf(": %q", highlight.Text)
+	if highlight.Note != "" {
+		entry += highlightNoteSeparator + highlight.Note
+	}
+	return entry
+}
+
+// parseHighlights reads the entries written by formatHighlight, skipping the
+// ones without a valid date or text.
+func parseHighlights(entries []string) []reading.Highlight {
+	highlights := []reading.Highlight{}
+	for _, entry := range entries {
+		if highlight, ok := parseHighlight(entry); ok {
+			highlights = append(highlights, highlight)
+		}
+	}
+	return highlights
+}
+
+func parseHighlight(entry string) (reading.Highlight, bool) {
+	head, rest, _ := strings.Cut(entry, ":")
+	dateStr, position, _ := strings.Cut(head, ",")
+	date := parseDate(strings.TrimSpace(dateStr))
+	if date == nil {
+		return reading.Highlight{}, false
+	}
+
+	highlight := reading.Highlight{Added: *date}
+	if _, number, found := strings.Cut(strings.TrimSpace(position), " "); found {
+		highlight.Page, _ = strconv.Atoi(number)
+	}
+
+	rest = strings.TrimSpace(rest)
+	text, note := rest, ""
+	if strings.HasPrefix(rest, `"`) {
+		end := strings.LastIndex(rest, `"`+highlightNoteSeparator)
+		if end < 0 {
+			end = len(rest) - 1
+		} else {
+			note = rest[end+1+len(highlightNoteSeparator):]
+		}
+		quoted := rest[:end+1]
+		if unquoted, err := strconv.Unquote(quoted); err == nil {
+			text = unquoted
+		} else {
+			text = strings.Trim(quoted, `"`)
+		}
+	}
+
+	highlight.Text = strings.TrimSpace(text)
+	highlight.Note = strings.TrimSpace(note)
+	if highlight.Text == "" {
+		return reading.Highlight{}, false
+	}
+	return highlight, true
+}
+
+// highlightsNode writes highlights as a list of mappings.
+func highlightsNode(highlights []reading.Highlight) *yaml.Node {
+	list := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, highlight := range highlights {
+		entry := &yaml.Node{Kind: yaml.MappingNode}
+		entry.Content = append(entry.Content, stringNode("date"), plainNode(highlight.Added.Format("2006-01-02")))
+		if highlight.Page > 0 {
+			entry.Content = append(entry.Content, stringNode("page"), plainNode(strconv.Itoa(highlight.Page)))
+		}
+		entry.Content = append(entry.Content, stringNode("text"), stringNode(highlight.Text))
+		if highlight.Note != "" {
+			entry.Content = append(entry.Content, stringNode("note"), stringNode(highlight.Note))
+		}
+		list.Content = append(list.Content, entry)
+	}
+	return list
+}
+
+// noteHighlights reads the highlights of a note. Entries may also be
+// written as in reading.md.
+func noteHighlights(node *yaml.Node) []reading.Highlight {
+	highlights := []reading.Highlight{}
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return highlights
+	}
+
+	for _, entry := range node.Content {
+		switch entry.Kind {
+		case yaml.ScalarNode:
+			if highlight, ok := parseHighlight(entry.Value); ok {
+				highlights = append(highlights, highlight)
+			}
+		case yaml.MappingNode:
+			values := make(map[string]string)
+			for i := 0; i+1 < len(entry.Content); i += 2 {
+				values[entry.Content[i].Value] = strings.TrimSpace(entry.Content[i+1].Value)
+			}
+
+			date := parseDate(values["date"])
+			if date == nil || values["text"] == "" {
+				continue
+			}
+			highlight := reading.Highlight{Added: *date, Text: values["text"], Note: values["note"]}
+			highlight.Page, _ = strconv.Atoi(values["page"])
+			highlights = append(highlights, highlight)
+		}
+	}
+	return highlights
+}
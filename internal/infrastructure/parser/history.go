@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+	"gopkg.in/yaml.v3"
+)
+
+// formatReadThrough writes an earlier read as a list entry, e.g.
+// "2024-01-05 to 2024-03-01: ⭐⭐⭐⭐ Worth it the second time".
+func formatReadThrough(read reading.ReadThrough) string {
+	dates := "?"
+	if read.Finished != nil {
+		dates = read.Finished.Format("2006-01-02")
+	}
+	if read.Started != nil {
+		dates = read.Started.Format("2006-01-02") + " to " + dates
+	}
+
+	verdict := []string{}
+	if read.Rating != nil && read.Rating.Value() > 0 {
+		verdict = append(verdict, strings.Repeat("⭐", read.Rating.Value()))
+	}
+	if read.Review != "" {
+		verdict = append(verdict, read.Review)
+	}
+
+	if len(verdict) == 0 {
+		return dates
+	}
+	return dates + ": " + strings.Join(verdict, " ")
+}
+
+// parseReadThroughs reads the entries written by formatReadThrough,
+// skipping the ones without any valid date.
+func parseReadThroughs(entries []string) []reading.ReadThrough {
+	reads := []reading.ReadThrough{}
+	for _, entry := range entries {
+		dates, verdict, _ := strings.Cut(entry, ":")
+
+		read := reading.ReadThrough{}
+		started, finished, found := strings.Cut(dates, " to ")
+		if !found {
+			started, finished = "", dates
+		}
+		read.Started = parseDate(strings.TrimSpace(started))
+		read.Finished = parseDate(strings.TrimSpace(finished))
+		if read.Started == nil && read.Finished == nil {
+			continue
+		}
+
+		verdict = strings.TrimSpace(verdict)
+		if stars := strings.Count(verdict, "⭐"); stars > 0 && strings.HasPrefix(verdict, "⭐") {
+			if rating, err := reading.NewRating(stars); err == nil {
+				read.Rating = &rating
+			}
+			verdict = strings.TrimSpace(strings.TrimLeft(verdict, "⭐"))
+		}
+		read.Review = verdict
+
+		reads = append(reads, read)
+	}
+	return reads
+}
+
+// readThroughsNode writes earlier reads as a list of flow mappings.
+func readThroughsNode(reads []reading.ReadThrough) *yaml.Node {
+	list := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, read := range reads {
+		entry := &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+		if read.Started != nil {
+			entry.Content = append(entry.Content, stringNode("started"), plainNode(read.Started.Format("2006-01-02")))
+		}
+		if read.Finished != nil {
+			entry.Content = append(entry.Content, stringNode("finished"), plainNode(read.Finished.Format("2006-01-02")))
+		}
+		if read.Rating != nil && read.Rating.Value() > 0 {
+			entry.Content = append(entry.Content, stringNode("rating"), plainNode(strconv.Itoa(read.Rating.Value())))
+		}
+		if read.Review != "" {
+			entry.Content = append(entry.Content, stringNode("review"), stringNode(read.Review))
+		}
+		list.Content = append(list.Content, entry)
+	}
+	return list
+}
+
+// noteReadThroughs reads the earlier reads of a note. Entries may also be
+// written as in reading.md.
+func (rp *ReadingParser) noteReadThroughs(node *yaml.Node) []reading.ReadThrough {
+	reads := []reading.ReadThrough{}
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return reads
+	}
+
+	for _, entry := range node.Content {
+		switch entry.Kind {
+		case yaml.ScalarNode:
+			reads = append(reads, parseReadThroughs([]string{entry.Value})...)
+		case yaml.MappingNode:
+			values := make(map[string]string)
+			for i := 0; i+1 < len(entry.Content); i += 2 {
+				values[entry.Content[i].Value] = strings.TrimSpace(entry.Content[i+1].Value)
+			}
+
+			read := reading.ReadThrough{
+				Started:  parseDate(values["started"]),
+				Finished: parseDate(values["finished"]),
+				Review:   values["review"],
+			}
+			if read.Started == nil && read.Finished == nil {
+				continue
+			}
+			if value := rp.parseRating(values["rating"]); value > 0 {
+				if rating, err := reading.NewRating(value); err == nil {
+					read.Rating = &rating
+				}
+			}
+			reads = append(reads, read)
+		}
+	}
+	return reads
+}
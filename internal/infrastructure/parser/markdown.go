@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"strings"
@@ -27,21 +26,50 @@ func NewParser() *Parser {
 }
 
 type Document struct {
-	Metadata map[string]interface{}
-	Sections []Section
-	Raw      string
+	Metadata    map[string]interface{}
+	Frontmatter []string
+	Preamble    []string
+	Sections    []Section
+	Raw         string
 }
 
 type Section struct {
-	Title string
-	Level int
-	Items []Item
+	Title   string
+	Heading string
+	Level   int
+	Intro   []string
+	Items   []Item
+	Lines   []string
 }
 
 type Item struct {
 	Title      string
+	Heading    string
 	Properties map[string]string
+	Fields     []Property
 	Content    string
+	Body       []string
+}
+
+// Property is a "- **key**: value" line as it appears in the document.
+// Items are the entries of an indented list right below it, and ItemLines
+// the raw lines they were read from.
+type Property struct {
+	Key       string
+	Value     string
+	Line      string
+	Items     []string
+	ItemLines []string
+}
+
+// Lines returns the raw lines of the item, heading included.
+func (i Item) Lines() []string {
+	lines := []string{i.Heading}
+	for _, field := range i.Fields {
+		lines = append(lines, field.Line)
+		lines = append(lines, field.ItemLines...)
+	}
+	return append(lines, i.Body...)
 }
 
 func (p *Parser) Parse(content []byte) (*Document, error) {
@@ -51,6 +79,10 @@ func (p *Parser) Parse(content []byte) (*Document, error) {
 		Sections: []Section{},
 	}
 
+	// A single trailing newline terminates the last line rather than
+	// starting an empty one.
+	content = bytes.TrimSuffix(content, []byte("\n"))
+
 	lines := bytes.Split(content, []byte("\n"))
 	inFrontmatter := false
 	frontmatterLines := []string{}
@@ -67,6 +99,7 @@ func (p *Parser) Parse(content []byte) (*Document, error) {
 		if inFrontmatter {
 			if lineStr == "---" {
 				inFrontmatter = false
+				doc.Frontmatter = frontmatterLines
 				continue
 			}
 			frontmatterLines = append(frontmatterLines, lineStr)
@@ -82,76 +115,111 @@ func (p *Parser) Parse(content []byte) (*Document, error) {
 		}
 	}
 
-	mainContent := strings.Join(contentLines, "\n")
-	doc.Sections = p.parseSections(mainContent)
+	if len(contentLines) == 1 && contentLines[0] == "" {
+		contentLines = nil
+	}
+	doc.Preamble, doc.Sections = p.parseSections(contentLines)
 
 	return doc, nil
 }
 
-func (p *Parser) parseSections(content string) []Section {
+// parseSections splits the body into "## " sections holding "### " items.
+// Every line ends up in the preamble, a section intro, an item or an
+// item body, so the document can be written back without losing text.
+func (p *Parser) parseSections(lines []string) ([]string, []Section) {
+	preamble := []string{}
 	sections := []Section{}
-	scanner := bufio.NewScanner(strings.NewReader(content))
 
 	var currentSection *Section
 	var currentItem *Item
 	inItemProperties := false
 
-	for scanner.Scan() {
-		line := scanner.Text()
+	flushItem := func() {
+		if currentItem != nil {
+			currentItem.Content = joinContent(currentItem.Body)
+			currentSection.Items = append(currentSection.Items, *currentItem)
+			currentItem = nil
+		}
+	}
+
+	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
 
 		if strings.HasPrefix(line, "## ") {
+			flushItem()
 			if currentSection != nil {
 				sections = append(sections, *currentSection)
 			}
 			currentSection = &Section{
-				Title: strings.TrimPrefix(line, "## "),
-				Level: 2,
-				Items: []Item{},
+				Title:   strings.TrimPrefix(line, "## "),
+				Heading: line,
+				Level:   2,
+				Items:   []Item{},
 			}
-			currentItem = nil
 			inItemProperties = false
-		} else if strings.HasPrefix(line, "### ") && currentSection != nil {
-			if currentItem != nil {
-				currentSection.Items = append(currentSection.Items, *currentItem)
-			}
+			continue
+		}
+
+		if currentSection == nil {
+			preamble = append(preamble, line)
+			continue
+		}
+
+		currentSection.Lines = append(currentSection.Lines, line)
+
+		if strings.HasPrefix(line, "### ") {
+			flushItem()
 			title := strings.TrimPrefix(line, "### ")
 			title = strings.Trim(title, "[]")
 			currentItem = &Item{
 				Title:      title,
+				Heading:    line,
 				Properties: make(map[string]string),
 			}
 			inItemProperties = true
-		} else if strings.HasPrefix(trimmed, "- **") && currentItem != nil && inItemProperties {
+		} else if currentItem == nil {
+			currentSection.Intro = append(currentSection.Intro, line)
+		} else if inItemProperties && strings.HasPrefix(trimmed, "- **") && strings.Contains(trimmed, ":") {
 			parts := strings.SplitN(trimmed, ":", 2)
-			if len(parts) == 2 {
-				key := strings.TrimSuffix(strings.TrimPrefix(parts[0], "- **"), "**")
-				value := strings.TrimSpace(parts[1])
-				currentItem.Properties[key] = value
-			}
-		} else if trimmed == "" && inItemProperties {
+			key := strings.TrimSuffix(strings.TrimPrefix(parts[0], "- **"), "**")
+			value := strings.TrimSpace(parts[1])
+			currentItem.Properties[key] = value
+			currentItem.Fields = append(currentItem.Fields, Property{Key: key, Value: value, Line: line})
+		} else if inItemProperties && len(currentItem.Fields) > 0 && isNestedListItem(line) {
+			field := &currentItem.Fields[len(currentItem.Fields)-1]
+			field.Items = append(field.Items, strings.TrimSpace(strings.TrimPrefix(trimmed, "-")))
+			field.ItemLines = append(field.ItemLines, line)
+		} else {
 			inItemProperties = false
-			// Add current item when we finish reading its properties
-			if currentItem != nil && currentSection != nil {
-				currentSection.Items = append(currentSection.Items, *currentItem)
-				currentItem = nil
-			}
-		} else if currentItem != nil && !inItemProperties && trimmed != "" {
-			if currentItem.Content != "" {
-				currentItem.Content += "\n"
-			}
-			currentItem.Content += line
+			currentItem.Body = append(currentItem.Body, line)
 		}
 	}
 
-	if currentItem != nil && currentSection != nil {
-		currentSection.Items = append(currentSection.Items, *currentItem)
-	}
 	if currentSection != nil {
+		flushItem()
 		sections = append(sections, *currentSection)
 	}
 
-	return sections
+	return preamble, sections
+}
+
+// isNestedListItem reports whether line is an indented "- " list entry.
+func isNestedListItem(line string) bool {
+	trimmed := strings.TrimLeft(line, " \t")
+	return len(trimmed) < len(line) && strings.HasPrefix(trimmed, "- ")
+}
+
+// joinContent returns the free text of an item body without the blank
+// lines surrounding it.
+func joinContent(lines []string) string {
+	start, end := 0, len(lines)
+	for start < end && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	for end > start && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return strings.Join(lines[start:end], "\n")
 }
 
 func (p *Parser) parseNode(node ast.Node, source []byte) {
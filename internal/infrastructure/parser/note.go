@@ -0,0 +1,452 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+	"gopkg.in/yaml.v3"
+)
+
+// noteFields are the frontmatter keys of a note mapped onto reading.Item
+// fields: the item properties plus the title and status, which the reading
+// list holds in headings.
+var noteFields = keySet(append([]string{"title", "status"}, propertyKeys...))
+
+// note is a Markdown file made of a YAML frontmatter and a free text body.
+type note struct {
+	fields *yaml.Node
+	body   string
+}
+
+func splitNote(content []byte) (*note, error) {
+	n := &note{fields: &yaml.Node{Kind: yaml.MappingNode}, body: string(content)}
+
+	if !bytes.HasPrefix(content, []byte("---\n")) {
+		return n, nil
+	}
+
+	rest := content[len("---\n"):]
+	end := bytes.Index(rest, []byte("\n---"))
+	if end < 0 {
+		return n, nil
+	}
+
+	frontmatter := rest[:end+1]
+	body := rest[end+len("\n---"):]
+	body = bytes.TrimPrefix(body, []byte("\n"))
+	n.body = string(body)
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(frontmatter, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if len(doc.Content) > 0 {
+		if doc.Content[0].Kind != yaml.MappingNode {
+			return nil, errors.New("frontmatter is not a mapping")
+		}
+		n.fields = doc.Content[0]
+	}
+
+	return n, nil
+}
+
+func (n *note) get(key string) *yaml.Node {
+	for i := 0; i+1 < len(n.fields.Content); i += 2 {
+		if n.fields.Content[i].Value == key {
+			return n.fields.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func (n *note) scalar(key string) string {
+	if node := n.get(key); node != nil && node.Kind == yaml.ScalarNode {
+		return strings.TrimSpace(node.Value)
+	}
+	return ""
+}
+
+// ParseNote reads an item stored as its own note: the frontmatter holds the
+// item fields and the body its free text.
+func (rp *ReadingParser) ParseNote(content []byte) (*reading.Item, error) {
+	n, err := splitNote(content)
+	if err != nil {
+		return nil, err
+	}
+
+	title, err := reading.NewTitle(n.scalar("title"))
+	if err != nil {
+		return nil, err
+	}
+
+	author := reading.Author(n.scalar("author"))
+	if author == "" {
+		author = "Unknown"
+	}
+
+	itemType := rp.parseItemType(n.scalar("type"))
+	if itemType == "" {
+		itemType = reading.TypeBook
+	}
+
+	item, err := reading.NewItem(title, author, itemType)
+	if err != nil {
+		return nil, err
+	}
+
+	item.Status = reading.Status(n.scalar("status"))
+	if !item.Status.IsValid() {
+		item.Status = reading.StatusToRead
+	}
+	item.Priority = rp.parsePriority(n.scalar("priority"))
+	item.Tags = noteTags(n.get("tags"))
+
+	item.Metadata = reading.Metadata{
+		URL:    n.scalar("url"),
+		ISBN:   n.scalar("isbn"),
+		DOI:    n.scalar("doi"),
+		Venue:  n.scalar("venue"),
+		Notes:  n.scalar("notes"),
+		Review: n.scalar("review"),
+		Reason: n.scalar("reason"),
+	}
+	item.Metadata.Year, _ = strconv.Atoi(n.scalar("year"))
+	if authors := n.get("authors"); authors != nil && authors.Kind == yaml.SequenceNode {
+		for _, author := range authors.Content {
+			if name := strings.TrimSpace(author.Value); name != "" {
+				item.Metadata.Authors = append(item.Metadata.Authors, name)
+			}
+		}
+	}
+	if t := parseDate(n.scalar("added")); t != nil {
+		item.Metadata.Added = *t
+	}
+	item.Metadata.Started = parseDate(n.scalar("started"))
+	item.Metadata.Finished = parseDate(n.scalar("finished"))
+	item.Metadata.Paused = parseDate(n.scalar("paused"))
+	item.Metadata.Abandoned = parseDate(n.scalar("abandoned"))
+
+	item.Progress = parseProgressFields(n.scalar, itemType.ProgressUnit())
+
+	if ratingVal := rp.parseRating(n.scalar("rating")); ratingVal > 0 {
+		if rating, err := reading.NewRating(ratingVal); err == nil {
+			item.Rating = &rating
+		}
+	}
+
+	if sessions := noteSessions(n.get("sessions")); len(sessions) > 0 {
+		item.Sessions = sessions
+	}
+	if reads := rp.noteReadThroughs(n.get("reads")); len(reads) > 0 {
+		item.History = reads
+	}
+	if highlights := noteHighlights(n.get("highlights")); len(highlights) > 0 {
+		item.Highlights = highlights
+	}
+
+	added := ""
+	if !item.Metadata.Added.IsZero() {
+		added = item.Metadata.Added.Format("2006-01-02")
+	}
+	item.ID = reading.ItemID(n.scalar("id"))
+	if item.ID == "" {
+		item.ID = reading.GenerateID(string(item.Title), string(item.Author), added)
+	}
+
+	for i := 0; i+1 < len(n.fields.Content); i += 2 {
+		key := n.fields.Content[i].Value
+		if !noteFields[key] {
+			item.Extra.Properties = append(item.Extra.Properties, noteExtra(key, n.fields.Content[i+1]))
+		}
+	}
+	item.Extra.Body = strings.TrimSpace(n.body)
+
+	return item, nil
+}
+
+// RenderNote writes item as a note. original is the current content of the
+// note (empty for a new one): keys gitlife does not manage, the order of
+// the frontmatter and unchanged values keep their original form.
+func (rp *ReadingParser) RenderNote(item *reading.Item, original []byte) ([]byte, error) {
+	n, err := splitNote(original)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous map[string]string
+	if len(original) > 0 {
+		if old, err := rp.ParseNote(original); err == nil {
+			previous = nodeStrings(noteProperties(old))
+		}
+	}
+
+	current := noteProperties(item)
+	currentValues := nodeStrings(current)
+	extras := append([]reading.Property{}, item.Extra.Properties...)
+
+	fields := &yaml.Node{Kind: yaml.MappingNode}
+	emitted := make(map[string]bool)
+	for i := 0; i+1 < len(n.fields.Content); i += 2 {
+		key, value := n.fields.Content[i], n.fields.Content[i+1]
+
+		if noteFields[key.Value] {
+			if emitted[key.Value] {
+				continue
+			}
+			emitted[key.Value] = true
+
+			if previous != nil && currentValues[key.Value] == previous[key.Value] {
+				fields.Content = append(fields.Content, key, value)
+			} else if node := findNode(current, key.Value); node != nil {
+				fields.Content = append(fields.Content, key, node)
+			}
+			continue
+		}
+
+		for k, extra := range extras {
+			if extra.Key != key.Value {
+				continue
+			}
+			if !sameProperty(extra, noteExtra(key.Value, value)) {
+				value = extraNode(extra)
+			}
+			fields.Content = append(fields.Content, key, value)
+			extras = append(extras[:k], extras[k+1:]...)
+			break
+		}
+	}
+
+	for i := 0; i+1 < len(current); i += 2 {
+		key := current[i].Value
+		if !emitted[key] && (previous == nil || currentValues[key] != previous[key]) {
+			fields.Content = append(fields.Content, current[i], current[i+1])
+		}
+	}
+	for _, extra := range extras {
+		fields.Content = append(fields.Content, stringNode(extra.Key), extraNode(extra))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(fields); err != nil {
+		return nil, fmt.Errorf("failed to write frontmatter: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write frontmatter: %w", err)
+	}
+	buf.WriteString("---\n")
+
+	if len(original) > 0 && item.Extra.Body == strings.TrimSpace(n.body) {
+		buf.WriteString(n.body)
+	} else if item.Extra.Body != "" {
+		buf.WriteString("\n" + item.Extra.Body + "\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// noteProperties returns the frontmatter gitlife manages for item as
+// alternating key and value nodes, in the order written for new notes.
+func noteProperties(item *reading.Item) []*yaml.Node {
+	nodes := []*yaml.Node{}
+	add := func(key string, value *yaml.Node) {
+		nodes = append(nodes, stringNode(key), value)
+	}
+
+	add("id", stringNode(string(item.ID)))
+	add("title", stringNode(string(item.Title)))
+	add("type", stringNode(string(item.Type)))
+	add("author", stringNode(string(item.Author)))
+	add("status", stringNode(string(item.Status)))
+
+	if item.Priority != "" && item.Priority != reading.PriorityMedium {
+		add("priority", stringNode(string(item.Priority)))
+	}
+
+	if len(item.Tags) > 0 {
+		tags := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, tag := range item.Tags {
+			tags.Content = append(tags.Content, stringNode(string(tag)))
+		}
+		add("tags", tags)
+	}
+
+	if !item.Metadata.Added.IsZero() {
+		add("added", plainNode(item.Metadata.Added.Format("2006-01-02")))
+	}
+	if item.Metadata.Started != nil {
+		add("started", plainNode(item.Metadata.Started.Format("2006-01-02")))
+	}
+	if item.Metadata.Finished != nil {
+		add("finished", plainNode(item.Metadata.Finished.Format("2006-01-02")))
+	}
+	if item.Metadata.Paused != nil {
+		add("paused", plainNode(item.Metadata.Paused.Format("2006-01-02")))
+	}
+	if item.Metadata.Abandoned != nil {
+		add("abandoned", plainNode(item.Metadata.Abandoned.Format("2006-01-02")))
+	}
+
+	if item.Progress != nil {
+		if item.Progress.Percentage > 0 {
+			add("progress", plainNode(strconv.Itoa(item.Progress.Percentage)))
+		}
+		keys := progressKeys[item.Type.ProgressUnit()]
+		if item.Progress.CurrentPage > 0 {
+			add(keys[0], plainNode(strconv.Itoa(item.Progress.CurrentPage)))
+		}
+		if item.Progress.TotalPages > 0 {
+			add(keys[1], plainNode(strconv.Itoa(item.Progress.TotalPages)))
+		}
+	}
+
+	if item.Rating != nil && item.Rating.Value() > 0 {
+		add("rating", plainNode(strconv.Itoa(item.Rating.Value())))
+	}
+
+	if item.Metadata.URL != "" {
+		add("url", stringNode(item.Metadata.URL))
+	}
+	if item.Metadata.ISBN != "" {
+		add("isbn", stringNode(item.Metadata.ISBN))
+	}
+	if item.Metadata.DOI != "" {
+		add("doi", stringNode(item.Metadata.DOI))
+	}
+	if item.Metadata.Venue != "" {
+		add("venue", stringNode(item.Metadata.Venue))
+	}
+	if item.Metadata.Year > 0 {
+		add("year", plainNode(strconv.Itoa(item.Metadata.Year)))
+	}
+	if len(item.Metadata.Authors) > 0 {
+		authors := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, author := range item.Metadata.Authors {
+			authors.Content = append(authors.Content, stringNode(author))
+		}
+		add("authors", authors)
+	}
+	if item.Metadata.Notes != "" {
+		add("notes", stringNode(item.Metadata.Notes))
+	}
+	if item.Metadata.Review != "" {
+		add("review", stringNode(item.Metadata.Review))
+	}
+	if item.Metadata.Reason != "" {
+		add("reason", stringNode(item.Metadata.Reason))
+	}
+
+	if len(item.Sessions) > 0 {
+		add("sessions", sessionsNode(item.Sessions))
+	}
+	if len(item.History) > 0 {
+		add("reads", readThroughsNode(item.History))
+	}
+	if len(item.Highlights) > 0 {
+		add("highlights", highlightsNode(item.Highlights))
+	}
+
+	return nodes
+}
+
+func noteTags(node *yaml.Node) []reading.Tag {
+	tags := []reading.Tag{}
+	if node == nil {
+		return tags
+	}
+
+	values := []string{}
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			values = append(values, child.Value)
+		}
+	case yaml.ScalarNode:
+		values = strings.FieldsFunc(node.Value, func(r rune) bool {
+			return r == ',' || r == ' '
+		})
+	}
+
+	for _, value := range values {
+		if tag := strings.TrimPrefix(strings.TrimSpace(value), "#"); tag != "" {
+			tags = append(tags, reading.Tag(tag))
+		}
+	}
+	return tags
+}
+
+// noteExtra reads a key gitlife does not manage. A list of plain values is
+// kept as the items of the property, anything else as a string.
+func noteExtra(key string, node *yaml.Node) reading.Property {
+	if node.Kind == yaml.SequenceNode && len(node.Content) > 0 {
+		items := []string{}
+		for _, child := range node.Content {
+			if child.Kind != yaml.ScalarNode {
+				return reading.Property{Key: key, Value: nodeString(node)}
+			}
+			items = append(items, child.Value)
+		}
+		return reading.Property{Key: key, Items: items}
+	}
+	return reading.Property{Key: key, Value: nodeString(node)}
+}
+
+func extraNode(extra reading.Property) *yaml.Node {
+	if len(extra.Items) == 0 {
+		return stringNode(extra.Value)
+	}
+
+	list := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, item := range extra.Items {
+		list.Content = append(list.Content, stringNode(item))
+	}
+	return list
+}
+
+func nodeStrings(nodes []*yaml.Node) map[string]string {
+	values := make(map[string]string, len(nodes)/2)
+	for i := 0; i+1 < len(nodes); i += 2 {
+		values[nodes[i].Value] = nodeString(nodes[i+1])
+	}
+	return values
+}
+
+func findNode(nodes []*yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(nodes); i += 2 {
+		if nodes[i].Value == key {
+			return nodes[i+1]
+		}
+	}
+	return nil
+}
+
+// nodeString returns a scalar as is and any other node as flow YAML.
+func nodeString(node *yaml.Node) string {
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+
+	flow := *node
+	flow.Style = yaml.FlowStyle
+	out, err := yaml.Marshal(&flow)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// plainNode leaves the tag to the YAML resolver, so dates and numbers are
+// written unquoted.
+func plainNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
@@ -19,29 +19,76 @@ func NewReadingParser() *ReadingParser {
 }
 
 func (rp *ReadingParser) ParseDocument(content []byte) ([]*reading.Item, error) {
-	doc, err := rp.parser.Parse(content)
+	_, sections, err := rp.parse(content)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse document: %w", err)
+		return nil, err
 	}
 
 	items := []*reading.Item{}
+	for _, sectionItems := range sections {
+		for _, item := range sectionItems {
+			if item != nil {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// parse returns the document together with the reading items of each
+// section, indexed like doc.Sections[i].Items. Entries are nil for items
+// that could not be converted and for sections that are not a status.
+func (rp *ReadingParser) parse(content []byte) (*Document, [][]*reading.Item, error) {
+	doc, err := rp.parser.Parse(content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+
+	sections := make([][]*reading.Item, len(doc.Sections))
+	seen := make(map[reading.ItemID]bool)
+
+	for i, section := range doc.Sections {
+		sections[i] = make([]*reading.Item, len(section.Items))
 
-	for _, section := range doc.Sections {
 		status := rp.sectionToStatus(section.Title)
 		if status == "" {
 			continue
 		}
 
-		for _, item := range section.Items {
+		for j, item := range section.Items {
 			readingItem, err := rp.itemToReadingItem(item, status)
 			if err != nil {
 				continue
 			}
-			items = append(items, readingItem)
+			readingItem.ID = rp.uniqueID(readingItem, item.Properties["id"], seen)
+			seen[readingItem.ID] = true
+			sections[i][j] = readingItem
 		}
 	}
 
-	return items, nil
+	return doc, sections, nil
+}
+
+// uniqueID returns the persisted ID when present and unused. Items written
+// before IDs were persisted (or copied by hand) get an ID derived from their
+// title, author and added date, so it stays the same until the file is
+// rewritten with the ID in place.
+func (rp *ReadingParser) uniqueID(item *reading.Item, persisted string, seen map[reading.ItemID]bool) reading.ItemID {
+	if id := reading.ItemID(strings.TrimSpace(persisted)); id != "" && !seen[id] {
+		return id
+	}
+
+	added := ""
+	if !item.Metadata.Added.IsZero() {
+		added = item.Metadata.Added.Format("2006-01-02")
+	}
+
+	id := reading.GenerateID(string(item.Title), string(item.Author), added)
+	for n := 1; seen[id]; n++ {
+		id = reading.GenerateID(string(item.Title), string(item.Author), added, strconv.Itoa(n))
+	}
+	return id
 }
 
 func (rp *ReadingParser) sectionToStatus(title string) reading.Status {
@@ -52,8 +99,17 @@ func (rp *ReadingParser) sectionToStatus(title string) reading.Status {
 	title = strings.ReplaceAll(title, "📚", "")
 	title = strings.ReplaceAll(title, "📖", "")
 	title = strings.ReplaceAll(title, "✅", "")
+	title = strings.ReplaceAll(title, "⏸️", "")
+	title = strings.ReplaceAll(title, "🚫", "")
 	title = strings.TrimSpace(title)
 
+	// Checked first, so "Paused reading" is not taken for "reading"
+	if strings.Contains(title, "paused") || strings.Contains(title, "on hold") {
+		return reading.StatusPaused
+	}
+	if strings.Contains(title, "abandoned") || strings.Contains(title, "dropped") || strings.Contains(title, "gave up") {
+		return reading.StatusAbandoned
+	}
 	if strings.Contains(title, "to read") || strings.Contains(title, "backlog") {
 		return reading.StatusToRead
 	}
@@ -94,8 +150,12 @@ func (rp *ReadingParser) itemToReadingItem(item Item, status reading.Status) (*r
 
 	metadata := reading.Metadata{
 		URL:    item.Properties["url"],
+		ISBN:   item.Properties["isbn"],
+		DOI:    item.Properties["doi"],
+		Venue:  item.Properties["venue"],
 		Notes:  item.Properties["notes"],
 		Review: item.Properties["review"],
+		Reason: item.Properties["reason"],
 	}
 
 	if addedStr := item.Properties["added"]; addedStr != "" {
@@ -116,27 +176,35 @@ func (rp *ReadingParser) itemToReadingItem(item Item, status reading.Status) (*r
 		}
 	}
 
-	readingItem.Metadata = metadata
+	metadata.Paused = parseDate(item.Properties["paused"])
+	metadata.Year, _ = strconv.Atoi(item.Properties["year"])
+	metadata.Abandoned = parseDate(item.Properties["abandoned"])
 
-	if progressStr := item.Properties["progress"]; progressStr != "" {
-		if percentage := rp.parseProgress(progressStr); percentage > 0 {
-			readingItem.Progress = &reading.Progress{
-				Percentage: percentage,
-			}
-		}
-	}
+	readingItem.Metadata = metadata
 
-	if currentPageStr := item.Properties["current_page"]; currentPageStr != "" {
-		if currentPage, err := strconv.Atoi(currentPageStr); err == nil && readingItem.Progress != nil {
-			readingItem.Progress.CurrentPage = currentPage
+	for _, field := range item.Fields {
+		switch {
+		case field.Key == "sessions":
+			readingItem.Sessions = parseSessions(field.Items)
+		case field.Key == "reads":
+			readingItem.History = parseReadThroughs(field.Items)
+		case field.Key == "highlights":
+			readingItem.Highlights = parseHighlights(field.Items)
+		case field.Key == "authors":
+			readingItem.Metadata.Authors = field.Items
+		case !knownProperties[field.Key]:
+			readingItem.Extra.Properties = append(readingItem.Extra.Properties, reading.Property{
+				Key:   field.Key,
+				Value: field.Value,
+				Items: field.Items,
+			})
 		}
 	}
+	readingItem.Extra.Body = item.Content
 
-	if pagesStr := item.Properties["pages"]; pagesStr != "" {
-		if pages, err := strconv.Atoi(pagesStr); err == nil && readingItem.Progress != nil {
-			readingItem.Progress.TotalPages = pages
-		}
-	}
+	readingItem.Progress = parseProgressFields(func(key string) string {
+		return item.Properties[key]
+	}, itemType.ProgressUnit())
 
 	if ratingStr := item.Properties["rating"]; ratingStr != "" {
 		if ratingVal := rp.parseRating(ratingStr); ratingVal > 0 {
@@ -148,6 +216,36 @@ func (rp *ReadingParser) itemToReadingItem(item Item, status reading.Status) (*r
 	return readingItem, nil
 }
 
+// parseProgressFields reads the progress of an item from its properties.
+// The position and total are looked up under the keys of the item's unit
+// and then under current_page and pages, which older files use for every
+// type.
+func parseProgressFields(get func(key string) string, unit reading.ProgressUnit) *reading.Progress {
+	number := func(keys ...string) int {
+		for _, key := range keys {
+			if value := strings.TrimSpace(get(key)); value != "" {
+				n, _ := strconv.Atoi(value)
+				return n
+			}
+		}
+		return 0
+	}
+
+	keys := progressKeys[unit]
+	percentage, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(get("progress")), "%"))
+	current := number(keys[0], "current_page")
+	total := number(keys[1], "pages")
+
+	if percentage <= 0 && current <= 0 && total <= 0 {
+		return nil
+	}
+	return &reading.Progress{
+		Percentage:  percentage,
+		CurrentPage: current,
+		TotalPages:  total,
+	}
+}
+
 func (rp *ReadingParser) parseItemType(typeStr string) reading.ItemType {
 	switch strings.ToLower(typeStr) {
 	case "book":
@@ -158,6 +256,8 @@ func (rp *ReadingParser) parseItemType(typeStr string) reading.ItemType {
 		return reading.TypeVideo
 	case "course":
 		return reading.TypeCourse
+	case "paper":
+		return reading.TypePaper
 	default:
 		return ""
 	}
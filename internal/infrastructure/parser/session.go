@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+	"gopkg.in/yaml.v3"
+)
+
+// formatSession writes a session as a list entry, e.g.
+// "2025-03-14: pages 10-45, 30%, 25 min". The range is named after the
+// progress unit of the item.
+func formatSession(session reading.Session, unit reading.ProgressUnit) string {
+	parts := []string{}
+	if session.ToPage > 0 {
+		parts = append(parts, fmt.Sprintf("%s %d-%d", unit, session.FromPage, session.ToPage))
+	}
+	parts = append(parts, fmt.Sprintf("%d%%", session.Percentage))
+	if session.Minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%d min", session.Minutes))
+	}
+	return session.Date.Format("2006-01-02") + ": " + strings.Join(parts, ", ")
+}
+
+// parseSessions reads the entries written by formatSession, skipping the
+// ones without a valid date.
+func parseSessions(entries []string) []reading.Session {
+	sessions := []reading.Session{}
+	for _, entry := range entries {
+		if session, ok := parseSession(entry); ok {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+func parseSession(entry string) (reading.Session, bool) {
+	dateStr, rest, _ := strings.Cut(entry, ":")
+	date := parseDate(strings.TrimSpace(dateStr))
+	if date == nil {
+		return reading.Session{}, false
+	}
+
+	session := reading.Session{Date: *date}
+	for _, part := range strings.Split(rest, ",") {
+		part = strings.TrimSpace(part)
+		word, span, _ := strings.Cut(part, " ")
+		_, isUnit := progressKeys[reading.ProgressUnit(word)]
+		switch {
+		case isUnit && strings.Contains(span, "-"):
+			from, to, _ := strings.Cut(span, "-")
+			session.FromPage, _ = strconv.Atoi(strings.TrimSpace(from))
+			session.ToPage, _ = strconv.Atoi(strings.TrimSpace(to))
+		case strings.HasSuffix(part, "%"):
+			session.Percentage, _ = strconv.Atoi(strings.TrimSuffix(part, "%"))
+		case strings.HasSuffix(part, " min"):
+			session.Minutes, _ = strconv.Atoi(strings.TrimSuffix(part, " min"))
+		}
+	}
+	return session, true
+}
+
+// sessionsNode writes sessions as a list of flow mappings, one per line.
+func sessionsNode(sessions []reading.Session) *yaml.Node {
+	list := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, session := range sessions {
+		entry := &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+		add := func(key, value string) {
+			entry.Content = append(entry.Content, stringNode(key), plainNode(value))
+		}
+
+		add("date", session.Date.Format("2006-01-02"))
+		if session.ToPage > 0 {
+			add("from", strconv.Itoa(session.FromPage))
+			add("to", strconv.Itoa(session.ToPage))
+		}
+		add("progress", strconv.Itoa(session.Percentage))
+		if session.Minutes > 0 {
+			add("minutes", strconv.Itoa(session.Minutes))
+		}
+		list.Content = append(list.Content, entry)
+	}
+	return list
+}
+
+// noteSessions reads the sessions of a note. Entries may also be written
+// as in reading.md.
+func noteSessions(node *yaml.Node) []reading.Session {
+	sessions := []reading.Session{}
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return sessions
+	}
+
+	for _, entry := range node.Content {
+		switch entry.Kind {
+		case yaml.ScalarNode:
+			if session, ok := parseSession(entry.Value); ok {
+				sessions = append(sessions, session)
+			}
+		case yaml.MappingNode:
+			values := make(map[string]string)
+			for i := 0; i+1 < len(entry.Content); i += 2 {
+				values[entry.Content[i].Value] = strings.TrimSpace(entry.Content[i+1].Value)
+			}
+
+			date := parseDate(values["date"])
+			if date == nil {
+				continue
+			}
+			session := reading.Session{Date: *date}
+			session.FromPage, _ = strconv.Atoi(values["from"])
+			session.ToPage, _ = strconv.Atoi(values["to"])
+			session.Percentage, _ = strconv.Atoi(strings.TrimSuffix(values["progress"], "%"))
+			session.Minutes, _ = strconv.Atoi(values["minutes"])
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
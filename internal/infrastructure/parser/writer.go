@@ -0,0 +1,476 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+)
+
+// propertyKeys are the item properties mapped onto reading.Item fields,
+// both in the reading list and in the frontmatter of a note. Anything else
+// is kept verbatim in Item.Extra.
+var propertyKeys = []string{
+	"id", "type", "author", "tags", "priority",
+	"added", "started", "finished", "paused", "abandoned", "reason",
+	"progress", "current_page", "pages", "current_minute", "minutes", "current_lesson", "lessons",
+	"rating", "url", "isbn", "doi", "venue", "year", "authors",
+	"notes", "review", "sessions", "reads", "highlights",
+}
+
+// knownProperties are the property keys of an item in the reading list.
+var knownProperties = keySet(propertyKeys)
+
+func keySet(keys []string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+}
+
+// progressKeys are the properties holding the current position and the
+// total of an item, by progress unit.
+var progressKeys = map[reading.ProgressUnit][2]string{
+	reading.UnitPages:   {"current_page", "pages"},
+	reading.UnitMinutes: {"current_minute", "minutes"},
+	reading.UnitLessons: {"current_lesson", "lessons"},
+}
+
+var statusOrder = []reading.Status{
+	reading.StatusToRead,
+	reading.StatusReading,
+	reading.StatusPaused,
+	reading.StatusDone,
+	reading.StatusAbandoned,
+}
+
+var statusHeadings = map[reading.Status]string{
+	reading.StatusToRead:    "## 📚 To Read",
+	reading.StatusReading:   "## 📖 Reading",
+	reading.StatusPaused:    "## ⏸️ Paused",
+	reading.StatusDone:      "## ✅ Done",
+	reading.StatusAbandoned: "## 🚫 Abandoned",
+}
+
+// RenderDocument writes items as a reading list. original is the current
+// content of the file (empty for a new file): its frontmatter, free text,
+// unknown sections, item order and property layout are kept, and only the
+// parts of an item that actually changed are rewritten.
+func (rp *ReadingParser) RenderDocument(items []*reading.Item, original []byte) ([]byte, error) {
+	doc, parsed, err := rp.parse(original)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[reading.ItemID]*reading.Item)
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	sources := make(map[reading.ItemID]*Item)
+	previous := make(map[reading.ItemID]*reading.Item)
+	firstSection := make(map[reading.Status]int)
+	lastSection := -1
+
+	for i, section := range doc.Sections {
+		status := rp.sectionToStatus(section.Title)
+		if status == "" {
+			continue
+		}
+		if _, ok := firstSection[status]; !ok {
+			firstSection[status] = i
+		}
+		lastSection = i
+
+		for j, item := range parsed[i] {
+			if item != nil {
+				sources[item.ID] = &doc.Sections[i].Items[j]
+				previous[item.ID] = item
+			}
+		}
+	}
+
+	w := &lineWriter{}
+	w.write(renderFrontmatter(doc.Frontmatter, len(original) == 0)...)
+	if len(original) == 0 {
+		w.write("", "# Reading List", "")
+	} else {
+		w.write(doc.Preamble...)
+	}
+
+	placed := make(map[reading.ItemID]bool)
+	renderPending := func(status reading.Status) {
+		for _, item := range items {
+			if item.Status == status && !placed[item.ID] {
+				w.blank()
+				w.write(renderItem(item, sources[item.ID], previous[item.ID])...)
+				placed[item.ID] = true
+			}
+		}
+	}
+	renderMissingSections := func() {
+		for _, status := range statusOrder {
+			if _, ok := firstSection[status]; ok || !hasStatus(items, status, placed) {
+				continue
+			}
+			w.blank()
+			w.write(statusHeadings[status], "")
+			renderPending(status)
+		}
+	}
+
+	for i, section := range doc.Sections {
+		status := rp.sectionToStatus(section.Title)
+		if status == "" {
+			w.write(section.Heading)
+			w.write(section.Lines...)
+			continue
+		}
+
+		w.write(section.Heading)
+		w.write(section.Intro...)
+
+		for j, source := range section.Items {
+			old := parsed[i][j]
+			if old == nil {
+				w.write(source.Lines()...)
+				continue
+			}
+
+			item := byID[old.ID]
+			if item == nil || item.Status != status || placed[item.ID] {
+				continue
+			}
+			w.write(renderItem(item, &section.Items[j], old)...)
+			placed[item.ID] = true
+		}
+
+		if firstSection[status] == i {
+			renderPending(status)
+		}
+		if i == lastSection {
+			renderMissingSections()
+		}
+	}
+
+	if lastSection == -1 {
+		renderMissingSections()
+	}
+
+	return w.bytes(), nil
+}
+
+func renderFrontmatter(frontmatter []string, newFile bool) []string {
+	today := time.Now().Format("2006-01-02")
+
+	if frontmatter == nil {
+		if !newFile {
+			return nil
+		}
+		return []string{
+			"---",
+			"type: reading-list",
+			"created: " + today,
+			"updated: " + today,
+			"---",
+		}
+	}
+
+	lines := []string{"---"}
+	updated := false
+	for _, line := range frontmatter {
+		if strings.HasPrefix(line, "updated:") {
+			line = "updated: " + today
+			updated = true
+		}
+		lines = append(lines, line)
+	}
+	if !updated {
+		lines = append(lines, "updated: "+today)
+	}
+	return append(lines, "---")
+}
+
+// renderItem writes item using the layout of source, the item as it was
+// last read from the document. Properties whose value did not change keep
+// their original line; changed properties missing from the source are
+// appended in the default order.
+func renderItem(item *reading.Item, source *Item, old *reading.Item) []string {
+	heading := fmt.Sprintf("### [[%s]]", item.Title)
+	if source != nil {
+		if old != nil && old.Title == item.Title {
+			heading = source.Heading
+		} else if !strings.Contains(source.Heading, "[[") {
+			heading = fmt.Sprintf("### %s", item.Title)
+		}
+	}
+	lines := []string{heading}
+
+	current := itemProperties(item)
+	currentValues := propertyMap(current)
+	previousValues := map[string]reading.Property{}
+	if old != nil {
+		previousValues = propertyMap(itemProperties(old))
+	}
+
+	var fields []Property
+	if source != nil {
+		fields = source.Fields
+	}
+
+	emitted := make(map[string]bool)
+	if !hasField(fields, "id") {
+		lines = append(lines, propertyLine("id", string(item.ID)))
+		emitted["id"] = true
+	}
+
+	extras := append([]reading.Property{}, item.Extra.Properties...)
+	for _, field := range fields {
+		if knownProperties[field.Key] {
+			if emitted[field.Key] {
+				continue
+			}
+			emitted[field.Key] = true
+
+			property, ok := currentValues[field.Key]
+			if old != nil && sameProperty(property, previousValues[field.Key]) {
+				lines = append(lines, field.Line)
+				lines = append(lines, field.ItemLines...)
+			} else if ok {
+				lines = append(lines, propertyLines(property)...)
+			}
+			continue
+		}
+
+		for k, extra := range extras {
+			if extra.Key != field.Key {
+				continue
+			}
+			if sameProperty(extra, reading.Property{Key: field.Key, Value: field.Value, Items: field.Items}) {
+				lines = append(lines, field.Line)
+				lines = append(lines, field.ItemLines...)
+			} else {
+				lines = append(lines, propertyLines(extra)...)
+			}
+			extras = append(extras[:k], extras[k+1:]...)
+			break
+		}
+	}
+
+	for _, property := range current {
+		if !emitted[property.Key] && (old == nil || !sameProperty(property, previousValues[property.Key])) {
+			lines = append(lines, propertyLines(property)...)
+		}
+	}
+	for _, extra := range extras {
+		lines = append(lines, propertyLines(extra)...)
+	}
+
+	if source != nil && item.Extra.Body == source.Content {
+		return append(lines, source.Body...)
+	}
+
+	lines = append(lines, "")
+	if item.Extra.Body != "" {
+		lines = append(lines, strings.Split(item.Extra.Body, "\n")...)
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// itemProperties returns the properties gitlife manages for item, in the
+// order they are written for new items.
+func itemProperties(item *reading.Item) []reading.Property {
+	properties := []reading.Property{
+		{Key: "id", Value: string(item.ID)},
+		{Key: "type", Value: string(item.Type)},
+		{Key: "author", Value: string(item.Author)},
+	}
+	add := func(key, value string) {
+		properties = append(properties, reading.Property{Key: key, Value: value})
+	}
+
+	if len(item.Tags) > 0 {
+		tags := []string{}
+		for _, tag := range item.Tags {
+			tags = append(tags, "#"+string(tag))
+		}
+		add("tags", strings.Join(tags, " "))
+	}
+
+	if item.Priority != "" && item.Priority != reading.PriorityMedium {
+		add("priority", string(item.Priority))
+	}
+
+	if !item.Metadata.Added.IsZero() {
+		add("added", item.Metadata.Added.Format("2006-01-02"))
+	}
+
+	if item.Metadata.Started != nil {
+		add("started", item.Metadata.Started.Format("2006-01-02"))
+	}
+
+	if item.Metadata.Finished != nil {
+		add("finished", item.Metadata.Finished.Format("2006-01-02"))
+	}
+
+	if item.Metadata.Paused != nil {
+		add("paused", item.Metadata.Paused.Format("2006-01-02"))
+	}
+
+	if item.Metadata.Abandoned != nil {
+		add("abandoned", item.Metadata.Abandoned.Format("2006-01-02"))
+	}
+
+	if item.Metadata.Reason != "" {
+		add("reason", item.Metadata.Reason)
+	}
+
+	if item.Progress != nil {
+		if item.Progress.Percentage > 0 {
+			add("progress", fmt.Sprintf("%d%%", item.Progress.Percentage))
+		}
+		keys := progressKeys[item.Type.ProgressUnit()]
+		if item.Progress.CurrentPage > 0 {
+			add(keys[0], fmt.Sprintf("%d", item.Progress.CurrentPage))
+		}
+		if item.Progress.TotalPages > 0 {
+			add(keys[1], fmt.Sprintf("%d", item.Progress.TotalPages))
+		}
+	}
+
+	if item.Rating != nil && item.Rating.Value() > 0 {
+		add("rating", strings.Repeat("⭐", item.Rating.Value()))
+	}
+
+	if item.Metadata.URL != "" {
+		add("url", item.Metadata.URL)
+	}
+	if item.Metadata.ISBN != "" {
+		add("isbn", item.Metadata.ISBN)
+	}
+	if item.Metadata.DOI != "" {
+		add("doi", item.Metadata.DOI)
+	}
+	if item.Metadata.Venue != "" {
+		add("venue", item.Metadata.Venue)
+	}
+	if item.Metadata.Year > 0 {
+		add("year", fmt.Sprintf("%d", item.Metadata.Year))
+	}
+	if len(item.Metadata.Authors) > 0 {
+		properties = append(properties, reading.Property{Key: "authors", Items: item.Metadata.Authors})
+	}
+
+	if item.Metadata.Notes != "" {
+		add("notes", item.Metadata.Notes)
+	}
+
+	if item.Metadata.Review != "" {
+		add("review", item.Metadata.Review)
+	}
+
+	if len(item.Sessions) > 0 {
+		sessions := reading.Property{Key: "sessions"}
+		for _, session := range item.Sessions {
+			sessions.Items = append(sessions.Items, formatSession(session, item.Type.ProgressUnit()))
+		}
+		properties = append(properties, sessions)
+	}
+
+	if len(item.History) > 0 {
+		reads := reading.Property{Key: "reads"}
+		for _, read := range item.History {
+			reads.Items = append(reads.Items, formatReadThrough(read))
+		}
+		properties = append(properties, reads)
+	}
+
+	if len(item.Highlights) > 0 {
+		highlights := reading.Property{Key: "highlights"}
+		for _, highlight := range item.Highlights {
+			highlights.Items = append(highlights.Items, formatHighlight(highlight, item.Type.ProgressUnit()))
+		}
+		properties = append(properties, highlights)
+	}
+
+	return properties
+}
+
+func propertyMap(properties []reading.Property) map[string]reading.Property {
+	values := make(map[string]reading.Property, len(properties))
+	for _, property := range properties {
+		values[property.Key] = property
+	}
+	return values
+}
+
+func sameProperty(a, b reading.Property) bool {
+	if a.Value != b.Value || len(a.Items) != len(b.Items) {
+		return false
+	}
+	for i := range a.Items {
+		if a.Items[i] != b.Items[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func hasField(fields []Property, key string) bool {
+	for _, field := range fields {
+		if field.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func propertyLine(key, value string) string {
+	if value == "" {
+		return fmt.Sprintf("- **%s**:", key)
+	}
+	return fmt.Sprintf("- **%s**: %s", key, value)
+}
+
+// propertyLines writes a property followed by its nested list.
+func propertyLines(property reading.Property) []string {
+	lines := []string{propertyLine(property.Key, property.Value)}
+	for _, item := range property.Items {
+		lines = append(lines, "  - "+item)
+	}
+	return lines
+}
+
+func hasStatus(items []*reading.Item, status reading.Status, placed map[reading.ItemID]bool) bool {
+	for _, item := range items {
+		if item.Status == status && !placed[item.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+type lineWriter struct {
+	lines []string
+}
+
+func (w *lineWriter) write(lines ...string) {
+	w.lines = append(w.lines, lines...)
+}
+
+// blank makes sure the next line is separated from the previous one.
+func (w *lineWriter) blank() {
+	if len(w.lines) > 0 && strings.TrimSpace(w.lines[len(w.lines)-1]) != "" {
+		w.lines = append(w.lines, "")
+	}
+}
+
+func (w *lineWriter) bytes() []byte {
+	if len(w.lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(w.lines, "\n") + "\n")
+}
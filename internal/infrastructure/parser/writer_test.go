@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+)
+
+// updatedLine matches the frontmatter date RenderDocument sets to today.
+var updatedLine = regexp.MustCompile(`(?m)^updated: .*$`)
+
+func goldenFiles(t *testing.T) map[string][]byte {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no golden files in testdata")
+	}
+
+	files := make(map[string][]byte)
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[filepath.Base(path)] = content
+	}
+	return files
+}
+
+// withToday sets the updated date of the frontmatter to today, as
+// RenderDocument does on every write.
+func withToday(content []byte) string {
+	return updatedLine.ReplaceAllString(string(content), "updated: "+time.Now().Format("2006-01-02"))
+}
+
+func TestRenderDocumentRoundTrip(t *testing.T) {
+	rp := NewReadingParser()
+
+	for name, content := range goldenFiles(t) {
+		t.Run(name, func(t *testing.T) {
+			items, err := rp.ParseDocument(content)
+			if err != nil {
+				t.Fatalf("ParseDocument: %v", err)
+			}
+			if len(items) == 0 {
+				t.Fatal("no items parsed")
+			}
+
+			rendered, err := rp.RenderDocument(items, content)
+			if err != nil {
+				t.Fatalf("RenderDocument: %v", err)
+			}
+			if got, want := string(rendered), withToday(content); got != want {
+				t.Errorf("rendered document differs from the original\n--- got\n%s\n--- want\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestRenderDocumentEditChangesOnlyThatItem(t *testing.T) {
+	rp := NewReadingParser()
+
+	for name, content := range goldenFiles(t) {
+		t.Run(name, func(t *testing.T) {
+			items, err := rp.ParseDocument(content)
+			if err != nil {
+				t.Fatalf("ParseDocument: %v", err)
+			}
+
+			// The last item, so the lines of the items before it must not move
+			edited := items[len(items)-1]
+			edited.Priority = reading.PriorityLow
+			edited.Metadata.Notes = "Edited in a test."
+
+			rendered, err := rp.RenderDocument(items, content)
+			if err != nil {
+				t.Fatalf("RenderDocument: %v", err)
+			}
+
+			before := strings.Split(withToday(content), "\n")
+			after := strings.Split(string(rendered), "\n")
+			first, last := changedLines(before, after)
+			if first > last {
+				t.Fatal("the edit did not change the document")
+			}
+
+			start, end := itemLines(before, edited)
+			if first < start || last >= end {
+				t.Errorf("lines %d-%d changed, outside the lines %d-%d of %q\n--- got\n%s",
+					first+1, last+1, start+1, end, edited.Title, rendered)
+			}
+			if !strings.Contains(string(rendered), "- **notes**: Edited in a test.") {
+				t.Errorf("the edit is missing from the document\n%s", rendered)
+			}
+		})
+	}
+}
+
+// changedLines returns the first and last line of before that differ from
+// after, once the lines both share at the start and end are set aside.
+func changedLines(before, after []string) (int, int) {
+	first := 0
+	for first < len(before) && first < len(after) && before[first] == after[first] {
+		first++
+	}
+	last, other := len(before)-1, len(after)-1
+	for last >= first && other >= first && before[last] == after[other] {
+		last--
+		other--
+	}
+	if last < first && other >= first {
+		// Lines were only added, right after line first-1
+		return first - 1, first - 1
+	}
+	return first, last
+}
+
+// itemLines returns the range of lines of the item in the document, from
+// its heading up to the next heading.
+func itemLines(lines []string, item *reading.Item) (int, int) {
+	start := -1
+	for i, line := range lines {
+		if start == -1 {
+			if strings.HasPrefix(line, "### ") && strings.Contains(line, string(item.Title)) {
+				start = i
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "## ") || strings.HasPrefix(line, "### ") {
+			return start, i
+		}
+	}
+	return start, len(lines)
+}
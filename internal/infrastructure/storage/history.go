@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"reflect"
+
+	"github.com/wguilherme/gitlife/internal/config"
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+	"github.com/wguilherme/gitlife/internal/infrastructure/git"
+	"github.com/wguilherme/gitlife/internal/infrastructure/parser"
+)
+
+var errNoHistory = errors.New("item history needs git to be set up for the vault")
+
+// History returns the revisions of the item found in the commits that
+// changed the gitlife folder.
+func (r *MarkdownRepository) History(id reading.ItemID) ([]reading.Revision, error) {
+	if r.gitService == nil {
+		return nil, errNoHistory
+	}
+	return itemHistory(r.gitService, r.config, r.parser, id)
+}
+
+// History returns the revisions of the item found in the commits that
+// changed the gitlife folder.
+func (r *NoteRepository) History(id reading.ItemID) ([]reading.Revision, error) {
+	if r.gitService == nil {
+		return nil, errNoHistory
+	}
+	return itemHistory(r.gitService, r.config, r.parser, id)
+}
+
+// itemHistory walks the commits that changed the gitlife folder, oldest
+// first, and keeps those that changed the item. The item is looked up in
+// the reading list and in the reading notes a commit changed, so its
+// history survives a migration between the two layouts and renamed notes.
+func itemHistory(gitService *git.Service, cfg *config.Config, p *parser.ReadingParser, id reading.ItemID) ([]reading.Revision, error) {
+	folder := filepath.ToSlash(cfg.GitLifeFolder)
+	document := path.Join(folder, "reading.md")
+	notes := path.Join(folder, "reading")
+
+	commits, err := gitService.Log(folder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the vault history: %w", err)
+	}
+
+	revisions := []reading.Revision{}
+	var inDocument, inNote, previous *reading.Item
+	notePath := ""
+	for _, commit := range commits {
+		for _, file := range commit.Files {
+			switch {
+			case file == document:
+				inDocument = nil
+				if content, err := gitService.Show(commit.Hash, file); err == nil {
+					inDocument = findRevision(p, content, id)
+				}
+			case path.Dir(file) == notes && path.Ext(file) == ".md":
+				content, err := gitService.Show(commit.Hash, file)
+				if err != nil {
+					if file == notePath {
+						inNote, notePath = nil, ""
+					}
+					continue
+				}
+				if item, err := p.ParseNote(content); err == nil && item.ID == id {
+					inNote, notePath = item, file
+				} else if file == notePath {
+					inNote, notePath = nil, ""
+				}
+			}
+		}
+
+		current := inNote
+		if current == nil {
+			current = inDocument
+		}
+		if reflect.DeepEqual(current, previous) {
+			continue
+		}
+		previous = current
+
+		revisions = append(revisions, reading.Revision{
+			Hash:    commit.Hash,
+			Author:  commit.Author,
+			Date:    commit.Date,
+			Message: commit.Subject,
+			Item:    current,
+		})
+	}
+	return revisions, nil
+}
+
+func findRevision(p *parser.ReadingParser, content []byte, id reading.ItemID) *reading.Item {
+	items, err := p.ParseDocument(content)
+	if err != nil {
+		return nil
+	}
+	for _, item := range items {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
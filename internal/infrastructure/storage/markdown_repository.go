@@ -1,13 +1,10 @@
 package storage
 
 import (
-	"bytes"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
-	"strings"
-	"time"
 
 	"github.com/wguilherme/gitlife/internal/config"
 	"github.com/wguilherme/gitlife/internal/domain/reading"
@@ -20,6 +17,8 @@ type MarkdownRepository struct {
 	parser     *parser.ReadingParser
 	gitService *git.Service
 	config     *config.Config
+	// change describes the writes, set by WithChange
+	change *reading.Change
 }
 
 func NewMarkdownRepository(vaultPath string) *MarkdownRepository {
@@ -100,23 +99,45 @@ func (r *MarkdownRepository) FindByTag(tag reading.Tag) ([]*reading.Item, error)
 
 	filtered := []*reading.Item{}
 	for _, item := range items {
-		for _, itemTag := range item.Tags {
-			if itemTag == tag {
-				filtered = append(filtered, item)
-				break
-			}
+		if item.TaggedWith(tag) {
+			filtered = append(filtered, item)
 		}
 	}
 
 	return filtered, nil
 }
 
+func (r *MarkdownRepository) Find(options reading.QueryOptions) ([]*reading.Item, int, error) {
+	items, err := r.FindAll()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page, total := reading.Query(items, options)
+	return page, total, nil
+}
+
 func (r *MarkdownRepository) Save(item *reading.Item) error {
 	items, err := r.FindAll()
 	if err != nil {
 		return err
 	}
 
+	for _, existing := range items {
+		if existing.ID == item.ID {
+			return fmt.Errorf("item with ID %s already exists", item.ID)
+		}
+	}
+
+	return r.writeToFile(append(items, item), reading.Change{Operation: "add", Items: []*reading.Item{item}})
+}
+
+func (r *MarkdownRepository) Update(item *reading.Item) error {
+	items, err := r.FindAll()
+	if err != nil {
+		return err
+	}
+
 	found := false
 	for i, existing := range items {
 		if existing.ID == item.ID {
@@ -127,14 +148,10 @@ func (r *MarkdownRepository) Save(item *reading.Item) error {
 	}
 
 	if !found {
-		items = append(items, item)
+		return fmt.Errorf("item with ID %s not found", item.ID)
 	}
 
-	return r.writeToFile(items)
-}
-
-func (r *MarkdownRepository) Update(item *reading.Item) error {
-	return r.Save(item)
+	return r.writeToFile(items, reading.Change{Operation: "edit", Items: []*reading.Item{item}})
 }
 
 func (r *MarkdownRepository) Delete(id reading.ItemID) error {
@@ -144,59 +161,42 @@ func (r *MarkdownRepository) Delete(id reading.ItemID) error {
 	}
 
 	filtered := []*reading.Item{}
+	deleted := reading.Change{Operation: "delete"}
 	for _, item := range items {
 		if item.ID != id {
 			filtered = append(filtered, item)
+		} else {
+			deleted.Items = append(deleted.Items, item)
 		}
 	}
 
-	return r.writeToFile(filtered)
+	return r.writeToFile(filtered, deleted)
 }
 
-func (r *MarkdownRepository) writeToFile(items []*reading.Item) error {
-	var buf bytes.Buffer
-
-	buf.WriteString("---\n")
-	buf.WriteString("type: reading-list\n")
-	buf.WriteString(fmt.Sprintf("created: %s\n", time.Now().Format("2006-01-02")))
-	buf.WriteString(fmt.Sprintf("updated: %s\n", time.Now().Format("2006-01-02")))
-	buf.WriteString("---\n\n")
-	buf.WriteString("# Reading List\n\n")
-
-	toRead := []*reading.Item{}
-	readingItems := []*reading.Item{}
-	done := []*reading.Item{}
-
-	for _, item := range items {
-		switch item.Status {
-		case reading.StatusToRead:
-			toRead = append(toRead, item)
-		case reading.StatusReading:
-			readingItems = append(readingItems, item)
-		case reading.StatusDone:
-			done = append(done, item)
-		}
-	}
+// ReplaceAll writes items as the whole reading list in a single write.
+func (r *MarkdownRepository) ReplaceAll(items []*reading.Item) error {
+	return r.writeToFile(items, reading.Change{Operation: "update"})
+}
 
-	if len(toRead) > 0 {
-		buf.WriteString("## 📚 To Read\n\n")
-		for _, item := range toRead {
-			r.writeItem(&buf, item)
-		}
-	}
+// WithChange returns a copy of the repository whose writes are committed
+// with a message describing change.
+func (r *MarkdownRepository) WithChange(change reading.Change) reading.Repository {
+	described := *r
+	described.change = &change
+	return &described
+}
 
-	if len(readingItems) > 0 {
-		buf.WriteString("## 📖 Reading\n\n")
-		for _, item := range readingItems {
-			r.writeItem(&buf, item)
-		}
+// writeToFile writes items and commits them, describing the write with
+// the change set by WithChange, or fallback without one.
+func (r *MarkdownRepository) writeToFile(items []*reading.Item, fallback reading.Change) error {
+	original, err := os.ReadFile(r.filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	if len(done) > 0 {
-		buf.WriteString("## ✅ Done\n\n")
-		for _, item := range done {
-			r.writeItem(&buf, item)
-		}
+	content, err := r.parser.RenderDocument(items, original)
+	if err != nil {
+		return err
 	}
 
 	dir := filepath.Dir(r.filePath)
@@ -204,106 +204,20 @@ func (r *MarkdownRepository) writeToFile(items []*reading.Item) error {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	if err := os.WriteFile(r.filePath, buf.Bytes(), 0644); err != nil {
+	if err := os.WriteFile(r.filePath, content, 0644); err != nil {
 		return err
 	}
 
 	// Auto-commit and push if git is configured
 	if r.gitService != nil && r.config != nil && r.config.AutoCommit {
-		if err := r.gitCommitAndPush("Update reading list"); err != nil {
-			log.Printf("Warning: git commit/push failed: %v", err)
+		change := fallback
+		if r.change != nil {
+			change = *r.change
 		}
-	}
-
-	return nil
-}
-
-func (r *MarkdownRepository) gitCommitAndPush(message string) error {
-	// Add the gitlife folder
-	gitlifeFolder := r.config.GitLifeFolder
-	if err := r.gitService.Add([]string{gitlifeFolder + "/"}); err != nil {
-		return fmt.Errorf("git add failed: %w", err)
-	}
-
-	// Commit
-	if message == "" {
-		message = r.config.CommitMessage
-	}
-	if err := r.gitService.Commit(message); err != nil {
-		return fmt.Errorf("git commit failed: %w", err)
-	}
-
-	// Push if auto-sync is enabled
-	if r.config.AutoSync {
-		if err := r.gitService.Push(); err != nil {
-			return fmt.Errorf("git push failed: %w", err)
+		if err := commitAndPush(r.gitService, r.config, change); err != nil {
+			log.Printf("Warning: git commit/push failed: %v", err)
 		}
 	}
 
 	return nil
 }
-
-func (r *MarkdownRepository) writeItem(buf *bytes.Buffer, item *reading.Item) {
-	buf.WriteString(fmt.Sprintf("### [[%s]]\n", item.Title))
-
-	buf.WriteString(fmt.Sprintf("- **type**: %s\n", item.Type))
-	buf.WriteString(fmt.Sprintf("- **author**: %s\n", item.Author))
-
-	if len(item.Tags) > 0 {
-		tags := []string{}
-		for _, tag := range item.Tags {
-			tags = append(tags, "#"+string(tag))
-		}
-		buf.WriteString(fmt.Sprintf("- **tags**: %s\n", strings.Join(tags, " ")))
-	}
-
-	if item.Priority != "" && item.Priority != reading.PriorityMedium {
-		buf.WriteString(fmt.Sprintf("- **priority**: %s\n", item.Priority))
-	}
-
-	if !item.Metadata.Added.IsZero() {
-		buf.WriteString(fmt.Sprintf("- **added**: %s\n", item.Metadata.Added.Format("2006-01-02")))
-	}
-
-	if item.Metadata.Started != nil {
-		buf.WriteString(fmt.Sprintf("- **started**: %s\n", item.Metadata.Started.Format("2006-01-02")))
-	}
-
-	if item.Metadata.Finished != nil {
-		buf.WriteString(fmt.Sprintf("- **finished**: %s\n", item.Metadata.Finished.Format("2006-01-02")))
-	}
-
-	if item.Progress != nil {
-		if item.Progress.Percentage > 0 {
-			buf.WriteString(fmt.Sprintf("- **progress**: %d%%\n", item.Progress.Percentage))
-		}
-		if item.Progress.CurrentPage > 0 {
-			buf.WriteString(fmt.Sprintf("- **current_page**: %d\n", item.Progress.CurrentPage))
-		}
-		if item.Progress.TotalPages > 0 {
-			buf.WriteString(fmt.Sprintf("- **pages**: %d\n", item.Progress.TotalPages))
-		}
-	}
-
-	if item.Rating != nil {
-		stars := ""
-		for i := 0; i < item.Rating.Value(); i++ {
-			stars += "⭐"
-		}
-		buf.WriteString(fmt.Sprintf("- **rating**: %s\n", stars))
-	}
-
-	if item.Metadata.URL != "" {
-		buf.WriteString(fmt.Sprintf("- **url**: %s\n", item.Metadata.URL))
-	}
-
-	if item.Metadata.Notes != "" {
-		buf.WriteString(fmt.Sprintf("- **notes**: %s\n", item.Metadata.Notes))
-	}
-
-	if item.Metadata.Review != "" {
-		buf.WriteString(fmt.Sprintf("- **review**: %s\n", item.Metadata.Review))
-	}
-
-	buf.WriteString("\n")
-}
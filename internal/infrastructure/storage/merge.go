@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"bytes"
+	"path"
+	"path/filepath"
+
+	"github.com/wguilherme/gitlife/internal/config"
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+	"github.com/wguilherme/gitlife/internal/infrastructure/parser"
+)
+
+// Merger merges the reading list, or a reading note, item by item when git
+// cannot merge it line by line, so two devices editing the list at the same
+// time only need a manual merge when they changed the same property of the
+// same item.
+type Merger struct {
+	folder string
+	parser *parser.ReadingParser
+}
+
+func NewMerger(cfg *config.Config) *Merger {
+	return &Merger{
+		folder: filepath.ToSlash(cfg.GitLifeFolder),
+		parser: parser.NewReadingParser(),
+	}
+}
+
+// Handles reports whether file, relative to the vault, is the reading list
+// or one of the reading notes.
+func (m *Merger) Handles(file string) bool {
+	file = filepath.ToSlash(file)
+	if file == path.Join(m.folder, "reading.md") {
+		return true
+	}
+	return path.Dir(file) == path.Join(m.folder, "reading") && path.Ext(file) == ".md"
+}
+
+// Merge merges the local (ours) and remote (theirs) versions of file, which
+// both started from base, and returns the conflicts it could not resolve.
+// A file outside the gitlife folder is taken for a note when it reads as
+// one and for a reading list otherwise.
+func (m *Merger) Merge(file string, base, ours, theirs []byte) ([]byte, []string, error) {
+	file = filepath.ToSlash(file)
+	if path.Base(file) == "reading.md" {
+		return m.mergeDocument(base, ours, theirs)
+	}
+	if !m.Handles(file) {
+		if _, err := m.parser.ParseNote(ours); err != nil {
+			return m.mergeDocument(base, ours, theirs)
+		}
+	}
+	return m.mergeNote(base, ours, theirs)
+}
+
+// mergeDocument merges the items of the reading list. The text around the
+// items (frontmatter, free text and sections gitlife does not manage) comes
+// from the side that changed it; changed on both sides, it is a conflict.
+func (m *Merger) mergeDocument(base, ours, theirs []byte) ([]byte, []string, error) {
+	versions := make([][]*reading.Item, 3)
+	for k, content := range [][]byte{base, ours, theirs} {
+		items, err := m.parser.ParseDocument(content)
+		if err != nil {
+			return nil, nil, err
+		}
+		versions[k] = items
+	}
+
+	items, found := reading.MergeVersions(versions[0], versions[1], versions[2])
+	conflicts := describeConflicts(found)
+
+	layouts := make([][]byte, 3)
+	for k, content := range [][]byte{base, ours, theirs} {
+		layout, err := m.parser.RenderDocument(nil, content)
+		if err != nil {
+			return nil, nil, err
+		}
+		layouts[k] = layout
+	}
+
+	original := ours
+	switch {
+	case bytes.Equal(layouts[1], layouts[2]) || bytes.Equal(layouts[2], layouts[0]):
+	case bytes.Equal(layouts[1], layouts[0]):
+		original = theirs
+	default:
+		conflicts = append(conflicts, "the text around the items changed on both sides")
+	}
+
+	content, err := m.parser.RenderDocument(items, original)
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, conflicts, nil
+}
+
+func (m *Merger) mergeNote(base, ours, theirs []byte) ([]byte, []string, error) {
+	var original *reading.Item
+	if len(bytes.TrimSpace(base)) > 0 {
+		item, err := m.parser.ParseNote(base)
+		if err != nil {
+			return nil, nil, err
+		}
+		original = item
+	}
+	local, err := m.parser.ParseNote(ours)
+	if err != nil {
+		return nil, nil, err
+	}
+	remote, err := m.parser.ParseNote(theirs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	item, found := reading.MergeItem(original, local, remote)
+	content, err := m.parser.RenderNote(item, ours)
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, describeConflicts(found), nil
+}
+
+func describeConflicts(conflicts []reading.Conflict) []string {
+	descriptions := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		descriptions = append(descriptions, conflict.String())
+	}
+	return descriptions
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/wguilherme/gitlife/internal/config"
+)
+
+const baseList = `# Reading List
+
+## 📚 To Read
+
+### [[The Pragmatic Programmer]]
+- **id**: 4f1c2a9e
+- **type**: book
+- **author**: Andrew Hunt, David Thomas
+- **tags**: #programming
+- **priority**: medium
+- **added**: 2024-11-02
+
+## 📖 Reading
+
+### [[Dune]]
+- **id**: 6632e679
+- **type**: book
+- **author**: Frank Herbert
+- **tags**: #scifi
+- **added**: 2024-12-01
+- **pages**: 412
+- **started**: 2025-01-03
+- **progress**: 30%
+- **current_page**: 124
+`
+
+// ours moved Dune on and raised the priority of the other book.
+const oursList = `# Reading List
+
+## 📚 To Read
+
+### [[The Pragmatic Programmer]]
+- **id**: 4f1c2a9e
+- **type**: book
+- **author**: Andrew Hunt, David Thomas
+- **tags**: #programming
+- **priority**: high
+- **added**: 2024-11-02
+
+## 📖 Reading
+
+### [[Dune]]
+- **id**: 6632e679
+- **type**: book
+- **author**: Frank Herbert
+- **tags**: #scifi
+- **added**: 2024-12-01
+- **pages**: 412
+- **started**: 2025-01-03
+- **progress**: 48%
+- **current_page**: 200
+`
+
+// theirs tagged Dune and added a book.
+const theirsList = `# Reading List
+
+## 📚 To Read
+
+### [[The Pragmatic Programmer]]
+- **id**: 4f1c2a9e
+- **type**: book
+- **author**: Andrew Hunt, David Thomas
+- **tags**: #programming
+- **priority**: medium
+- **added**: 2024-11-02
+
+### [[Project Hail Mary]]
+- **id**: 91b07e3d
+- **type**: book
+- **author**: Andy Weir
+- **added**: 2025-01-10
+
+## 📖 Reading
+
+### [[Dune]]
+- **id**: 6632e679
+- **type**: book
+- **author**: Frank Herbert
+- **tags**: #scifi #favorite
+- **added**: 2024-12-01
+- **pages**: 412
+- **started**: 2025-01-03
+- **progress**: 30%
+- **current_page**: 124
+`
+
+const mergedList = `# Reading List
+
+## 📚 To Read
+
+### [[The Pragmatic Programmer]]
+- **id**: 4f1c2a9e
+- **type**: book
+- **author**: Andrew Hunt, David Thomas
+- **tags**: #programming
+- **priority**: high
+- **added**: 2024-11-02
+
+### [[Project Hail Mary]]
+- **id**: 91b07e3d
+- **type**: book
+- **author**: Andy Weir
+- **added**: 2025-01-10
+
+## 📖 Reading
+
+### [[Dune]]
+- **id**: 6632e679
+- **type**: book
+- **author**: Frank Herbert
+- **tags**: #scifi #favorite
+- **added**: 2024-12-01
+- **pages**: 412
+- **started**: 2025-01-03
+- **progress**: 48%
+- **current_page**: 200
+`
+
+func TestMergerMergesReadingList(t *testing.T) {
+	merger := NewMerger(&config.Config{GitLifeFolder: "gitlife"})
+
+	content, conflicts, err := merger.Merge("gitlife/reading.md", []byte(baseList), []byte(oursList), []byte(theirsList))
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if len(conflicts) > 0 {
+		t.Errorf("unexpected conflicts: %q", conflicts)
+	}
+	if string(content) != mergedList {
+		t.Errorf("merged list differs\n--- got\n%s\n--- want\n%s", content, mergedList)
+	}
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/wguilherme/gitlife/internal/config"
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+	"github.com/wguilherme/gitlife/internal/infrastructure/git"
+	"github.com/wguilherme/gitlife/internal/infrastructure/parser"
+)
+
+// Migrate moves the reading list to the target storage layout
+// (config.StorageSingleFile or config.StorageNotes) and removes the files of
+// the previous layout. Everything is committed at once when git is
+// configured. It returns the number of items moved.
+func Migrate(cfg *config.Config, gitService *git.Service, target string) (int, error) {
+	if gitService != nil && cfg.AutoSync {
+		if err := gitService.Pull(); err != nil {
+			log.Printf("Warning: git pull failed: %v", err)
+		}
+	}
+
+	// Repositories without git, so nothing is committed halfway through
+	folder := filepath.Join(cfg.VaultPath, cfg.GitLifeFolder)
+	single := &MarkdownRepository{
+		filePath: filepath.Join(folder, "reading.md"),
+		parser:   parser.NewReadingParser(),
+	}
+	notes := &NoteRepository{
+		dir:    filepath.Join(folder, "reading"),
+		parser: parser.NewReadingParser(),
+	}
+
+	var from, to reading.Repository
+	var cleanup func() error
+	switch target {
+	case config.StorageNotes:
+		from, to = single, notes
+		cleanup = func() error {
+			return os.Remove(single.filePath)
+		}
+	case config.StorageSingleFile:
+		from, to = notes, single
+		cleanup = func() error {
+			if err := notes.ReplaceAll(nil); err != nil {
+				return err
+			}
+			// Only removed when nothing but our notes was in it
+			os.Remove(notes.dir)
+			return nil
+		}
+	default:
+		return 0, fmt.Errorf("unknown storage layout %q", target)
+	}
+
+	items, err := from.FindAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read current layout: %w", err)
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	existing, err := to.FindAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read target layout: %w", err)
+	}
+	if len(existing) > 0 {
+		return 0, fmt.Errorf("the %s layout already holds %d items", target, len(existing))
+	}
+
+	if err := to.ReplaceAll(items); err != nil {
+		return 0, fmt.Errorf("failed to write items: %w", err)
+	}
+	if err := cleanup(); err != nil {
+		return 0, fmt.Errorf("failed to remove previous layout: %w", err)
+	}
+
+	if gitService != nil && cfg.AutoCommit {
+		change := reading.Change{Operation: "migrate", Detail: fmt.Sprintf("to %s storage", target)}
+		if err := commitAndPush(gitService, cfg, change); err != nil {
+			log.Printf("Warning: git commit/push failed: %v", err)
+		}
+	}
+
+	return len(items), nil
+}
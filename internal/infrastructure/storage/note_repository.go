@@ -0,0 +1,338 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wguilherme/gitlife/internal/config"
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+	"github.com/wguilherme/gitlife/internal/infrastructure/git"
+	"github.com/wguilherme/gitlife/internal/infrastructure/parser"
+)
+
+// NoteRepository stores each reading item as its own Markdown note, so the
+// vault works like any other Obsidian folder and concurrent edits to
+// different items never touch the same file.
+type NoteRepository struct {
+	dir        string
+	parser     *parser.ReadingParser
+	gitService *git.Service
+	config     *config.Config
+	// change describes the writes, set by WithChange
+	change *reading.Change
+}
+
+type noteFile struct {
+	path string
+	item *reading.Item
+}
+
+func NewNoteRepository(vaultPath string) *NoteRepository {
+	return &NoteRepository{
+		dir:    filepath.Join(vaultPath, "gitlife", "reading"),
+		parser: parser.NewReadingParser(),
+	}
+}
+
+func NewNoteRepositoryWithGit(cfg *config.Config, gitService *git.Service) *NoteRepository {
+	return &NoteRepository{
+		dir:        filepath.Join(cfg.VaultPath, cfg.GitLifeFolder, "reading"),
+		parser:     parser.NewReadingParser(),
+		gitService: gitService,
+		config:     cfg,
+	}
+}
+
+func (r *NoteRepository) FindAll() ([]*reading.Item, error) {
+	files, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	items := []*reading.Item{}
+	for _, file := range files {
+		items = append(items, file.item)
+	}
+	return items, nil
+}
+
+func (r *NoteRepository) FindByID(id reading.ItemID) (*reading.Item, error) {
+	file, err := r.find(id)
+	if err != nil {
+		return nil, err
+	}
+	return file.item, nil
+}
+
+func (r *NoteRepository) FindByStatus(status reading.Status) ([]*reading.Item, error) {
+	items, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []*reading.Item{}
+	for _, item := range items {
+		if item.Status == status {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *NoteRepository) FindByTag(tag reading.Tag) ([]*reading.Item, error) {
+	items, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []*reading.Item{}
+	for _, item := range items {
+		if item.TaggedWith(tag) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *NoteRepository) Find(options reading.QueryOptions) ([]*reading.Item, int, error) {
+	items, err := r.FindAll()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page, total := reading.Query(items, options)
+	return page, total, nil
+}
+
+func (r *NoteRepository) Save(item *reading.Item) error {
+	files, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if file.item.ID == item.ID {
+			return fmt.Errorf("item with ID %s already exists", item.ID)
+		}
+	}
+
+	if err := r.writeNote(r.newPath(item, files), item); err != nil {
+		return err
+	}
+	r.commit(reading.Change{Operation: "add", Items: []*reading.Item{item}})
+	return nil
+}
+
+func (r *NoteRepository) Update(item *reading.Item) error {
+	file, err := r.find(item.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := r.writeNote(file.path, item); err != nil {
+		return err
+	}
+	r.commit(reading.Change{Operation: "edit", Items: []*reading.Item{item}})
+	return nil
+}
+
+func (r *NoteRepository) Delete(id reading.ItemID) error {
+	file, err := r.find(id)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(file.path); err != nil {
+		return fmt.Errorf("failed to delete note: %w", err)
+	}
+	r.commit(reading.Change{Operation: "delete", Items: []*reading.Item{file.item}})
+	return nil
+}
+
+// ReplaceAll makes the folder hold exactly items, writing only the notes
+// that changed and committing once.
+func (r *NoteRepository) ReplaceAll(items []*reading.Item) error {
+	files, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	paths := make(map[reading.ItemID]string)
+	for _, file := range files {
+		paths[file.item.ID] = file.path
+	}
+
+	keep := make(map[string]bool)
+	for _, item := range items {
+		path, ok := paths[item.ID]
+		if !ok {
+			path = r.newPath(item, files)
+			files = append(files, noteFile{path: path, item: item})
+		}
+		keep[path] = true
+
+		if err := r.writeNote(path, item); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range files {
+		if !keep[file.path] {
+			if err := os.Remove(file.path); err != nil {
+				return fmt.Errorf("failed to delete note: %w", err)
+			}
+		}
+	}
+
+	r.commit(reading.Change{Operation: "update"})
+	return nil
+}
+
+// WithChange returns a copy of the repository whose writes are committed
+// with a message describing change.
+func (r *NoteRepository) WithChange(change reading.Change) reading.Repository {
+	described := *r
+	described.change = &change
+	return &described
+}
+
+func (r *NoteRepository) load() ([]noteFile, error) {
+	// Pull latest changes if git is configured
+	if r.gitService != nil && r.config != nil && r.config.AutoSync {
+		if err := r.gitService.Pull(); err != nil {
+			log.Printf("Warning: git pull failed: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []noteFile{}, nil
+		}
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	files := []noteFile{}
+	seen := make(map[reading.ItemID]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+
+		path := filepath.Join(r.dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read note: %w", err)
+		}
+
+		item, err := r.parser.ParseNote(content)
+		if err != nil {
+			log.Printf("Warning: skipping %s: %v", path, err)
+			continue
+		}
+
+		// A note copied by hand keeps the ID of the original
+		if seen[item.ID] {
+			item.ID = reading.GenerateID(string(item.ID), entry.Name())
+		}
+		seen[item.ID] = true
+
+		files = append(files, noteFile{path: path, item: item})
+	}
+
+	return files, nil
+}
+
+func (r *NoteRepository) find(id reading.ItemID) (*noteFile, error) {
+	files, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		if file.item.ID == id {
+			return &file, nil
+		}
+	}
+
+	return nil, fmt.Errorf("item with ID %s not found", id)
+}
+
+func (r *NoteRepository) writeNote(path string, item *reading.Item) error {
+	original, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read note: %w", err)
+	}
+
+	content, err := r.parser.RenderNote(item, original)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(content, original) {
+		return nil
+	}
+
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	return os.WriteFile(path, content, 0644)
+}
+
+// commit describes the write with the change set by WithChange, or
+// fallback without one.
+func (r *NoteRepository) commit(fallback reading.Change) {
+	change := fallback
+	if r.change != nil {
+		change = *r.change
+	}
+
+	// Auto-commit and push if git is configured
+	if r.gitService != nil && r.config != nil && r.config.AutoCommit {
+		if err := commitAndPush(r.gitService, r.config, change); err != nil {
+			log.Printf("Warning: git commit/push failed: %v", err)
+		}
+	}
+}
+
+// newPath names the note after the item title, the way Obsidian would, and
+// falls back to adding the ID when the name is already taken.
+func (r *NoteRepository) newPath(item *reading.Item, files []noteFile) string {
+	name := noteFileName(string(item.Title))
+	if name == "" {
+		name = string(item.ID)
+	}
+
+	taken := func(path string) bool {
+		for _, file := range files {
+			if strings.EqualFold(file.path, path) {
+				return true
+			}
+		}
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	path := filepath.Join(r.dir, name+".md")
+	if taken(path) {
+		path = filepath.Join(r.dir, fmt.Sprintf("%s (%s).md", name, item.ID))
+	}
+	return path
+}
+
+// noteFileName replaces the characters Obsidian does not allow in note names.
+func noteFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|#^[]`, r) {
+			return ' '
+		}
+		return r
+	}, title)
+	return strings.Join(strings.Fields(name), " ")
+}
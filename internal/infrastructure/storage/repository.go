@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/wguilherme/gitlife/internal/config"
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+	"github.com/wguilherme/gitlife/internal/infrastructure/git"
+)
+
+// NewRepository returns the reading repository for the configured storage
+// layout. gitService may be nil when the vault is not a git repository;
+// otherwise it is set to merge the reading files item by item on pull.
+func NewRepository(cfg *config.Config, gitService *git.Service) reading.Repository {
+	if gitService != nil {
+		gitService.SetMerger(NewMerger(cfg))
+	}
+
+	if cfg.Storage == config.StorageNotes {
+		if gitService != nil {
+			return NewNoteRepositoryWithGit(cfg, gitService)
+		}
+		return NewNoteRepository(cfg.VaultPath)
+	}
+
+	if gitService != nil {
+		return NewMarkdownRepositoryWithGit(cfg, gitService)
+	}
+	return NewMarkdownRepository(cfg.VaultPath)
+}
+
+func commitAndPush(gitService *git.Service, cfg *config.Config, change reading.Change) error {
+	// Committing now would conclude the merge with its conflicts unresolved
+	if gitService.MergeInProgress() {
+		return fmt.Errorf("a merge is waiting for manual resolution, sync the vault once it is resolved")
+	}
+
+	// Add the gitlife folder
+	gitlifeFolder := cfg.GitLifeFolder
+	if err := gitService.Add([]string{gitlifeFolder + "/"}); err != nil {
+		return fmt.Errorf("git add failed: %w", err)
+	}
+
+	// Commit
+	if err := gitService.Commit(commitMessage(cfg, change)); err != nil {
+		return fmt.Errorf("git commit failed: %w", err)
+	}
+
+	// Push if auto-sync is enabled
+	if cfg.AutoSync {
+		if err := gitService.Push(); err != nil {
+			return fmt.Errorf("git push failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// commitData is what the commit template can use.
+type commitData struct {
+	Operation string // e.g. start, import or rename-tag
+	Subject   string // the quoted title, "3 items" or the detail of the change
+	Title     string // title of the first item
+	ID        string // ID of the first item
+	Count     int    // number of items touched
+}
+
+// commitMessage describes change with the configured template and adds
+// the Gitlife-Op and Gitlife-Item trailers, one per item touched.
+func commitMessage(cfg *config.Config, change reading.Change) string {
+	data := commitData{Operation: change.Operation, Count: len(change.Items)}
+	if len(change.Items) > 0 {
+		data.Title = string(change.Items[0].Title)
+		data.ID = string(change.Items[0].ID)
+	}
+	switch {
+	case change.Detail != "":
+		data.Subject = change.Detail
+	case len(change.Items) == 1:
+		data.Subject = "'" + data.Title + "'"
+	case len(change.Items) > 1:
+		data.Subject = fmt.Sprintf("%d items", len(change.Items))
+	default:
+		data.Subject = "reading list"
+	}
+
+	message, err := renderCommitMessage(cfg.CommitTemplate, data)
+	if err != nil {
+		log.Printf("Warning: commit template failed, using the default: %v", err)
+		message, _ = renderCommitMessage(config.DefaultCommitTemplate, data)
+	}
+
+	lines := []string{message, "", "Gitlife-Op: " + change.Operation}
+	for _, item := range change.Items {
+		lines = append(lines, "Gitlife-Item: "+string(item.ID))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderCommitMessage(text string, data commitData) (string, error) {
+	tmpl, err := template.New("commit").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var message strings.Builder
+	if err := tmpl.Execute(&message, data); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(message.String()) == "" {
+		return "", fmt.Errorf("the message is empty")
+	}
+	return strings.TrimSpace(message.String()), nil
+}
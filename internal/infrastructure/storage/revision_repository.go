@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/wguilherme/gitlife/internal/config"
+	"github.com/wguilherme/gitlife/internal/domain/reading"
+	"github.com/wguilherme/gitlife/internal/infrastructure/git"
+	"github.com/wguilherme/gitlife/internal/infrastructure/parser"
+)
+
+var errNoRevisions = errors.New("reading the list as of a date or revision needs git to be set up for the vault")
+
+// snapshotDates are the dates a snapshot can be taken at. A date stands
+// for its end, so 2025-03 is the reading list as March 2025 left it.
+var snapshotDates = []struct {
+	layout string
+	years  int
+	months int
+	days   int
+}{
+	{"2006", 1, 0, 0},
+	{"2006-01", 0, 1, 0},
+	{"2006-01-02", 0, 0, 1},
+}
+
+// RevisionRepository is the reading list as a commit of the vault left it,
+// read with git show instead of from the working tree. It is read-only.
+type RevisionRepository struct {
+	gitService *git.Service
+	folder     string
+	parser     *parser.ReadingParser
+	commit     *git.Commit
+	time       time.Time
+}
+
+// NewRevisionRepository opens the reading list as of at: a date (2025,
+// 2025-03, 2025-03-14 or an RFC 3339 time), which picks the last commit up
+// to it, or a git revision such as a commit hash, a tag or HEAD~3. Either
+// layout is read, whichever the commit holds.
+func NewRevisionRepository(cfg *config.Config, gitService *git.Service, at string) (*RevisionRepository, error) {
+	if gitService == nil || !gitService.RepoExists() {
+		return nil, errNoRevisions
+	}
+
+	repo := &RevisionRepository{
+		gitService: gitService,
+		folder:     filepath.ToSlash(cfg.GitLifeFolder),
+		parser:     parser.NewReadingParser(),
+	}
+
+	var err error
+	if moment, ok := snapshotTime(at); ok {
+		repo.time = moment
+		repo.commit, err = gitService.RevisionAt(moment)
+	} else {
+		repo.commit, err = gitService.Revision(at)
+		if err == nil {
+			repo.time = repo.commit.Date
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// snapshotTime reads at as a date, in local time, and returns the last
+// moment it covers.
+func snapshotTime(at string) (time.Time, bool) {
+	if moment, err := time.Parse(time.RFC3339, at); err == nil {
+		return moment, true
+	}
+	for _, date := range snapshotDates {
+		if start, err := time.ParseInLocation(date.layout, at, time.Local); err == nil {
+			return start.AddDate(date.years, date.months, date.days).Add(-time.Second), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Commit is the commit the reading list is read from.
+func (r *RevisionRepository) Commit() *git.Commit {
+	return r.commit
+}
+
+// Time is the date asked for or, for a revision, the date of its commit.
+func (r *RevisionRepository) Time() time.Time {
+	return r.time
+}
+
+func (r *RevisionRepository) FindAll() ([]*reading.Item, error) {
+	files, err := r.gitService.Files(r.commit.Hash, r.folder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the vault as of %s: %w", r.commit.Hash[:7], err)
+	}
+
+	document := path.Join(r.folder, "reading.md")
+	notes := path.Join(r.folder, "reading")
+
+	items := []*reading.Item{}
+	seen := make(map[reading.ItemID]bool)
+	for _, file := range files {
+		switch {
+		case file == document:
+			content, err := r.gitService.Show(r.commit.Hash, file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			found, err := r.parser.ParseDocument(content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s as of %s: %w", file, r.commit.Hash[:7], err)
+			}
+			items = append(items, found...)
+		case path.Dir(file) == notes && path.Ext(file) == ".md":
+			content, err := r.gitService.Show(r.commit.Hash, file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			item, err := r.parser.ParseNote(content)
+			if err != nil {
+				log.Printf("Warning: skipping %s: %v", file, err)
+				continue
+			}
+
+			// A note copied by hand keeps the ID of the original
+			if seen[item.ID] {
+				item.ID = reading.GenerateID(string(item.ID), path.Base(file))
+			}
+			seen[item.ID] = true
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *RevisionRepository) FindByID(id reading.ItemID) (*reading.Item, error) {
+	items, err := r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+
+	return nil, fmt.Errorf("item with ID %s not found", id)
+}
+
+func (r *RevisionRepository) FindByStatus(status reading.Status) ([]*reading.Item, error) {
+	items, _, err := r.Find(reading.QueryOptions{Status: &status})
+	return items, err
+}
+
+func (r *RevisionRepository) FindByTag(tag reading.Tag) ([]*reading.Item, error) {
+	items, _, err := r.Find(reading.QueryOptions{Tags: []reading.Tag{tag}})
+	return items, err
+}
+
+func (r *RevisionRepository) Find(options reading.QueryOptions) ([]*reading.Item, int, error) {
+	items, err := r.FindAll()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page, total := reading.Query(items, options)
+	return page, total, nil
+}
+
+func (r *RevisionRepository) Save(item *reading.Item) error {
+	return r.readOnly()
+}
+
+func (r *RevisionRepository) Update(item *reading.Item) error {
+	return r.readOnly()
+}
+
+func (r *RevisionRepository) Delete(id reading.ItemID) error {
+	return r.readOnly()
+}
+
+func (r *RevisionRepository) ReplaceAll(items []*reading.Item) error {
+	return r.readOnly()
+}
+
+func (r *RevisionRepository) readOnly() error {
+	return fmt.Errorf("the reading list as of %s is read-only", r.commit.Hash[:7])
+}